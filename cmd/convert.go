@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/roverliang/grpc2openapi/openapi/genopenapi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertOutput  string
+	convertVersion string
+)
+
+func init() {
+	ConvertCommand.Flags().StringVarP(&convertOutput, "output", "o", "-", "where to write the converted document, `-` for stdout")
+	ConvertCommand.Flags().StringVar(&convertVersion, "to", "3.0", "OpenAPI version to convert to, 3.0 or 3.1")
+}
+
+var ConvertCommand = &cobra.Command{
+	Use:   "convert <swagger.json>",
+	Short: "upgrade a swagger 2.0 document to OpenAPI 3",
+	Long: "convert reads a swagger 2.0 document in JSON or YAML, generated or hand-written, and renders it as an " +
+		"OpenAPI 3.0 or 3.1 document with the same serializers gen uses.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, err := readSwaggerDocument(args[0])
+		if err != nil {
+			return err
+		}
+
+		out, err := genopenapi.Convert(in, convertVersion)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s: %v", args[0], err)
+		}
+		return writeOutput(convertOutput, out)
+	},
+}
@@ -1,12 +1,14 @@
 package cmd
 
 import (
+	"fmt"
 	"github.com/roverliang/grpc2openapi/openapi"
 	"github.com/roverliang/grpc2openapi/openapi/descriptor"
 	"github.com/roverliang/grpc2openapi/openapi/genopenapi"
 	"github.com/spf13/cobra"
 	"io/ioutil"
 	"k8s.io/klog/v2"
+	"path"
 	"strings"
 )
 
@@ -30,6 +32,22 @@ var (
 	openAPIConfiguration       string
 	generateUnboundMethods     bool
 	namespace string
+	onlyFiles                  []string
+	excludeFiles               []string
+	lenientLoad                bool
+	fieldPathCommentsOrder     string
+	unboundReport              string
+	failOnUnbound              bool
+	listExtensions             string
+	namingStability            string
+	namingManifest             string
+	packagePathSegments        int
+	emitGRPCWebPaths           bool
+	bodyExamples               bool
+	responseEnvelope           string
+	constraintsConfiguration   string
+	titlesFile                 string
+	rateLimitConfiguration     string
 )
 
 func init() {
@@ -50,14 +68,35 @@ func init() {
 	GenCommand.Flags().BoolVar(&disableDefaultErrors, "disable_default_errors", true, "if set, disables generation of default errors. This is useful if you have defined custom error handling")
 	GenCommand.Flags().BoolVar(&enumsAsInts, "enums_as_ints", false, "whether to render enum values as integers, as opposed to string values")
 	GenCommand.Flags().BoolVar(&simpleOperationIDs, "simple_operation_ids", false, "whether to remove the service prefix in the operationID generation. Can introduce duplicate operationIDs, use with caution.")
-	GenCommand.Flags().StringVar(&openAPIConfiguration, "openapi_configuration", "", "path to file which describes the OpenAPI Configuration in YAML format")
+	GenCommand.Flags().StringVar(&openAPIConfiguration, "openapi_configuration", "", "path to file which describes the OpenAPI Configuration in YAML format, including response_overrides of services and methods")
 	GenCommand.Flags().BoolVar(&generateUnboundMethods, "generate_unbound_methods", true, "generate swagger metadata even for RPC methods that have no HttpRule annotation")
+	GenCommand.Flags().StringSliceVar(&onlyFiles, "only-files", nil, "if set, only proto files whose name matches one of these glob patterns become generation targets")
+	GenCommand.Flags().StringSliceVar(&excludeFiles, "exclude-files", nil, "proto files whose name matches one of these glob patterns are never generation targets, e.g. vendored third-party protos")
+	GenCommand.Flags().BoolVar(&lenientLoad, "lenient-load", false, "if set, proto files that fail to load are skipped with a warning instead of aborting the generation")
+	GenCommand.Flags().StringVar(&fieldPathCommentsOrder, "field_path_comments_order", "", "if set, the comments of all fields along a body or response_body field path are merged into the description. Allowed values are `outer_first` and `inner_first`")
+	GenCommand.Flags().StringVar(&unboundReport, "unbound-report", "", "if set, writes the list of RPC methods without google.api.http annotation to this file, `-` for stdout")
+	GenCommand.Flags().BoolVar(&failOnUnbound, "fail-on-unbound", false, "if set, fails the generation when any RPC method has no google.api.http annotation")
+	GenCommand.Flags().StringVar(&listExtensions, "list-extensions", "", "if set, writes the list of vendor extensions of the generated documents with their sources to this file, `-` for stdout")
+	GenCommand.Flags().IntVar(&packagePathSegments, "package-path-segments", 0, "if set, this many proto package segments ending with the version segment prefix the paths of unbound methods and namespaced paths, e.g. 2 maps package acme.billing.v2 to /billing/v2")
+	GenCommand.Flags().StringVar(&constraintsConfiguration, "constraints_configuration", "", "path to file which describes cross-field constraints of messages in YAML format, rendered as x-constraints")
+	GenCommand.Flags().StringVar(&titlesFile, "titles_file", "", "path to a YAML file which maps fully qualified message, enum and field names to localized titles")
+	GenCommand.Flags().StringVar(&rateLimitConfiguration, "ratelimit_configuration", "", "path to file which describes the rate limits of services and methods in YAML format, rendered as x-ratelimit")
+	GenCommand.Flags().StringVar(&responseEnvelope, "response-envelope", "", "if set, every success response is wrapped in this built-in envelope. Allowed values are `code-msg-data`, `code-message-data` and `jsonapi`")
+	GenCommand.Flags().BoolVar(&bodyExamples, "body-examples", false, "if set, request bodies without an explicit example get one built from proto default values, field default/example options and enum defaults")
+	GenCommand.Flags().BoolVar(&emitGRPCWebPaths, "emit-grpc-web-paths", false, "experimental: if set, the raw gRPC-Web endpoint /pkg.Service/Method of every method is documented in addition to its HTTP bindings")
+	GenCommand.Flags().StringVar(&namingStability, "naming-stability", "", "if set, definition names and operationIds are recorded in a naming manifest and never change between runs and tool versions. Allowed values are `v1`")
+	GenCommand.Flags().StringVar(&namingManifest, "naming-manifest", "", "naming manifest to read and update with --naming-stability, defaults to <merge_file_name>.naming.json")
 }
 
 var GenCommand = &cobra.Command{
 	Use:   "gen",
 	Short: "gen swagger api",
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := validateFilePatterns(append(onlyFiles, excludeFiles...)); err != nil {
+			klog.Error(err)
+			return
+		}
+
 		fds, err := openapi.LoadProtosetFile("/Users/roverliang/go/src/tds-service-agent/api.bin")
 
 		if err != nil {
@@ -94,12 +133,68 @@ var GenCommand = &cobra.Command{
 		reg.SetDisableDefaultErrors(disableDefaultErrors)
 		reg.SetSimpleOperationIDs(simpleOperationIDs)
 		reg.SetGenerateUnboundMethods(generateUnboundMethods)
+		reg.SetLenientLoad(lenientLoad)
+		reg.SetEmitGRPCWebPaths(emitGRPCWebPaths)
+		reg.SetGenerateBodyExamples(bodyExamples)
+		if err := reg.SetFieldPathCommentsOrder(fieldPathCommentsOrder); err != nil {
+			klog.Error(err)
+			return
+		}
+		if err := reg.SetResponseEnvelope(responseEnvelope); err != nil {
+			klog.Error(err)
+			return
+		}
+
+		if err := reg.SetPackagePathSegments(packagePathSegments); err != nil {
+			klog.Error(err)
+			return
+		}
+		if err := reg.SetNamingStability(namingStability); err != nil {
+			klog.Error(err)
+			return
+		}
+		manifestPath := namingManifestPath()
+		if manifestPath != "" {
+			m, err := descriptor.LoadNamingManifest(manifestPath, namingStability)
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+			if err := reg.SetNamingManifest(m); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
 
 		gen := genopenapi.New(reg)
 		if err := reg.Load(fds); err != nil {
-			klog.Info(err)
+			klog.Errorf("failed to load protoset: %v", err)
 			return
 		}
+		if openAPIConfiguration != "" {
+			if err := reg.LoadOpenAPIConfigFromYAML(openAPIConfiguration); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+		if titlesFile != "" {
+			if err := reg.LoadTitlesFromYAML(titlesFile); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+		if rateLimitConfiguration != "" {
+			if err := reg.LoadRateLimitConfigFromYAML(rateLimitConfiguration); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+		if constraintsConfiguration != "" {
+			if err := reg.LoadConstraintsConfigFromYAML(constraintsConfiguration); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
 
 		var targets []*descriptor.File
 		for _, f := range fds {
@@ -107,25 +202,191 @@ var GenCommand = &cobra.Command{
 				continue
 			}
 
+			// files without services are registered for lookups only
+			if !openapi.HasServices(f) {
+				continue
+			}
+
 			filePath := f.GetFile().GetName()
+			if !isTargetFile(filePath) {
+				klog.V(1).Infof("skip %s: filtered out by --only-files/--exclude-files", filePath)
+				continue
+			}
+
 			f, err := reg.LookupFile(filePath)
 			if err != nil {
+				if lenientLoad {
+					// the file was skipped by the registry, a warning is already logged
+					continue
+				}
 				klog.Fatal(err)
 			}
 			targets = append(targets, f)
 		}
 
+		unbound := unboundMethodsOf(reg, targets)
+		if unboundReport != "" {
+			if err := writeUnboundReport(unboundReport, unbound); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+		if failOnUnbound && len(unbound) > 0 {
+			klog.Fatalf("%d RPC method(s) have no google.api.http annotation, see --unbound-report for the list", len(unbound))
+		}
+
 		out, err := gen.Generate(targets)
 		if err != nil {
 			klog.Error(err)
 			return
 		}
 		emitResp(out)
+
+		if listExtensions != "" {
+			if err := writeExtensionsReport(listExtensions, out); err != nil {
+				klog.Error(err)
+				return
+			}
+		}
+
+		if manifestPath != "" {
+			content, err := reg.GetNamingManifest().Marshal()
+			if err != nil {
+				klog.Error(err)
+				return
+			}
+			if err := writeContentToFile(manifestPath, string(content)); err != nil {
+				klog.Errorf("failed to write naming manifest to %s: %v", manifestPath, err)
+				return
+			}
+		}
 	},
 }
 
+// namingManifestPath returns where the naming manifest is read from and
+// written to, or an empty string if naming stability is disabled.
+func namingManifestPath() string {
+	if namingStability == "" {
+		if namingManifest != "" {
+			klog.Warning("--naming-manifest is ignored without --naming-stability")
+		}
+		return ""
+	}
+	if namingManifest != "" {
+		return namingManifest
+	}
+	return mergeFileName + ".naming.json"
+}
+
+// unboundMethodsOf returns the unannotated methods of the generation targets.
+func unboundMethodsOf(reg *descriptor.Registry, targets []*descriptor.File) []descriptor.UnboundMethod {
+	targetNames := make(map[string]struct{}, len(targets))
+	for _, f := range targets {
+		targetNames[f.GetName()] = struct{}{}
+	}
+
+	var unbound []descriptor.UnboundMethod
+	for _, m := range reg.UnboundMethods() {
+		if _, ok := targetNames[m.Method.Service.File.GetName()]; ok {
+			unbound = append(unbound, m)
+		}
+	}
+	return unbound
+}
+
+// writeUnboundReport writes one line per unannotated method, telling whether
+// it was auto-bound (and to which route) or skipped.
+func writeUnboundReport(filePath string, unbound []descriptor.UnboundMethod) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# RPC methods without google.api.http annotation: %d\n", len(unbound))
+	for _, m := range unbound {
+		name := strings.TrimPrefix(m.Method.FQMN(), ".")
+		if !m.AutoBound || len(m.Method.Bindings) == 0 {
+			fmt.Fprintf(&b, "%s\tskipped\n", name)
+			continue
+		}
+		binding := m.Method.Bindings[0]
+		fmt.Fprintf(&b, "%s\tauto-bound\t%s %s\n", name, binding.HTTPMethod, binding.PathTmpl.Template)
+	}
+
+	if filePath == "-" {
+		_, err := fmt.Print(b.String())
+		return err
+	}
+	if err := writeContentToFile(filePath, b.String()); err != nil {
+		return fmt.Errorf("failed to write unbound report to %s: %v", filePath, err)
+	}
+	return nil
+}
+
+// writeExtensionsReport writes one line per vendor extension of the generated
+// files, telling where it is and where it comes from.
+func writeExtensionsReport(filePath string, files []*descriptor.ResponseFile) error {
+	count := 0
+	for _, file := range files {
+		count += len(file.Extensions)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# vendor extensions: %d\n", count)
+	for _, file := range files {
+		for _, ext := range file.Extensions {
+			location := ext.Location
+			if location == "" {
+				location = "/"
+			}
+			fmt.Fprintf(&b, "%s\t%s\t%s\t%s\n", file.GetName(), location, ext.Key, ext.Source)
+		}
+	}
+
+	if filePath == "-" {
+		_, err := fmt.Print(b.String())
+		return err
+	}
+	if err := writeContentToFile(filePath, b.String()); err != nil {
+		return fmt.Errorf("failed to write extensions report to %s: %v", filePath, err)
+	}
+	return nil
+}
+
+
+
 
+// validateFilePatterns makes sure every --only-files/--exclude-files pattern is a valid glob.
+func validateFilePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid file pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
 
+// isTargetFile reports whether the proto file should be passed to the generator.
+// Exclusion wins over inclusion, and an empty --only-files list includes everything.
+func isTargetFile(filePath string) bool {
+	if matchAnyFilePattern(excludeFiles, filePath) {
+		return false
+	}
+	if len(onlyFiles) == 0 {
+		return true
+	}
+	return matchAnyFilePattern(onlyFiles, filePath)
+}
+
+// matchAnyFilePattern matches the patterns against the full proto file name,
+// e.g. "google/api/annotations.proto", as well as its base name.
+func matchAnyFilePattern(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, filePath); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(filePath)); ok {
+			return true
+		}
+	}
+	return false
+}
 
 func emitResp(resp []*descriptor.ResponseFile) {
 	if len(resp) == 1 && allowMerge {
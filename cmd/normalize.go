@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/roverliang/grpc2openapi/openapi/genopenapi"
+	"github.com/spf13/cobra"
+)
+
+var normalizeOutput string
+
+func init() {
+	NormalizeCommand.Flags().StringVarP(&normalizeOutput, "output", "o", "-", "where to write the normalized document, `-` for stdout")
+}
+
+var NormalizeCommand = &cobra.Command{
+	Use:   "normalize <swagger.json>",
+	Short: "canonicalize an existing swagger document",
+	Long: "normalize reads a swagger 2.0 document in JSON or YAML, generated or hand-written, and renders it the way gen does: " +
+		"sorted keys, deduplicated lists, resolved simple references and normalized casing, so that documents can be compared.",
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		in, err := readSwaggerDocument(args[0])
+		if err != nil {
+			return err
+		}
+
+		out, err := genopenapi.Normalize(in)
+		if err != nil {
+			return fmt.Errorf("failed to normalize %s: %v", args[0], err)
+		}
+		return writeOutput(normalizeOutput, out)
+	},
+}
+
+// readSwaggerDocument reads a JSON or YAML document from a file, or from stdin if the name is `-`.
+func readSwaggerDocument(name string) ([]byte, error) {
+	var (
+		in  []byte
+		err error
+	)
+	if name == "-" {
+		in, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		in, err = ioutil.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml":
+		in, err = yaml.YAMLToJSON(in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s from YAML to JSON: %v", name, err)
+		}
+	}
+	return in, nil
+}
+
+// writeOutput writes content to a file, or to stdout if the name is `-`.
+func writeOutput(name string, content []byte) error {
+	if name == "-" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+	return writeContentToFile(name, string(content))
+}
@@ -14,6 +14,8 @@ var rootCommand = &cobra.Command{
 
 func main() {
 	rootCommand.AddCommand(cmd.GenCommand)
+	rootCommand.AddCommand(cmd.NormalizeCommand)
+	rootCommand.AddCommand(cmd.ConvertCommand)
 	err := rootCommand.Execute()
 	if err != nil {
 		klog.Error(err)
@@ -0,0 +1,112 @@
+package descriptor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Constraint describes a constraint between fields of a message, which
+// OpenAPI 2.0 can't express, e.g. "either email or phone is required".
+type Constraint struct {
+	// Type is one of ConstraintTypes.
+	Type string `json:"type"`
+	// Fields are the proto names of the constrained fields.
+	Fields []string `json:"fields"`
+	// Description explains the constraint, a description is derived from
+	// the type and fields if it is empty.
+	Description string `json:"description,omitempty"`
+}
+
+// ConstraintTypes maps the supported constraint types to the description
+// template of their fields.
+var ConstraintTypes = map[string]string{
+	"at_least_one":       "at least one of %s is required",
+	"exactly_one":        "exactly one of %s is required",
+	"at_most_one":        "at most one of %s may be set",
+	"all_or_none":        "%s must be set together or not at all",
+	"mutually_exclusive": "%s are mutually exclusive",
+}
+
+// constraintsConfig is the YAML configuration of message constraints, e.g.
+//
+//	messages:
+//	  acme.users.v1.CreateUserRequest:
+//	    - type: at_least_one
+//	      fields: [email, phone]
+//	      description: either email or phone is required
+type constraintsConfig struct {
+	Messages map[string][]Constraint `json:"messages"`
+}
+
+// LoadConstraintsConfigFromYAML loads the cross-field constraints of messages
+// from the given YAML file and registers them in the registry.
+// This must be done after loading the proto file.
+func (r *Registry) LoadConstraintsConfigFromYAML(yamlFile string) error {
+	yamlFileContents, err := ioutil.ReadFile(yamlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read constraints configuration from '%v': %v", yamlFile, err)
+	}
+
+	var config constraintsConfig
+	if err := yaml.Unmarshal(yamlFileContents, &config); err != nil {
+		return fmt.Errorf("failed to parse constraints configuration from YAML in '%v': %v", yamlFile, err)
+	}
+
+	for name, constraints := range config.Messages {
+		for _, c := range constraints {
+			if err := r.AddConstraint(name, c); err != nil {
+				return fmt.Errorf("invalid constraint in %s: %v", yamlFile, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AddConstraint registers a constraint of the message with the given fully
+// qualified name, after checking that its type and fields are known.
+func (r *Registry) AddConstraint(messageName string, c Constraint) error {
+	fqmn := "." + strings.TrimPrefix(messageName, ".")
+	msg, err := r.LookupMsg("", fqmn)
+	if err != nil {
+		return err
+	}
+	template, ok := ConstraintTypes[c.Type]
+	if !ok {
+		return fmt.Errorf("%s: unknown constraint type %q", messageName, c.Type)
+	}
+	if len(c.Fields) < 2 {
+		return fmt.Errorf("%s: a %s constraint needs at least two fields", messageName, c.Type)
+	}
+	for _, name := range c.Fields {
+		if !hasField(msg, name) {
+			return fmt.Errorf("%s: no such field %q", messageName, name)
+		}
+	}
+	if c.Description == "" {
+		c.Description = fmt.Sprintf(template, strings.Join(c.Fields, ", "))
+	}
+
+	if r.constraints == nil {
+		r.constraints = make(map[string][]Constraint)
+	}
+	r.constraints[fqmn] = append(r.constraints[fqmn], c)
+	return nil
+}
+
+// GetConstraints returns the constraints registered for the message with the
+// given fully qualified name.
+func (r *Registry) GetConstraints(fqmn string) []Constraint {
+	return r.constraints[fqmn]
+}
+
+func hasField(msg *Message, name string) bool {
+	for _, f := range msg.Fields {
+		if f.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
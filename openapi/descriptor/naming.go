@@ -0,0 +1,149 @@
+package descriptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+)
+
+// NamingStabilityV1 is the first naming stability version. Definition names
+// are derived from the shortest unique suffix of the fully qualified proto
+// name (or the full name with fqn_for_openapi_name), and operationIds are
+// "Service_Method" (or "Method" with simple_operation_ids), suffixed with the
+// binding index for additional bindings.
+//
+// The derivation of a version never changes. Names which are recorded in the
+// naming manifest are reused as they are, even if the derivation would give
+// a different name because other protos were added.
+const NamingStabilityV1 = "v1"
+
+// NamingManifest records the definition names and operationIds given to the
+// protos of an API, so that they are kept between runs and tool versions.
+type NamingManifest struct {
+	// Version is the naming stability version the names were derived with.
+	Version string `json:"version"`
+	// Definitions maps fully qualified message and enum names to definition names.
+	Definitions map[string]string `json:"definitions"`
+	// OperationIDs maps method keys, see OperationKey, to operationIds.
+	OperationIDs map[string]string `json:"operationIds"`
+
+	mu sync.Mutex
+}
+
+// NewNamingManifest returns an empty manifest for the naming stability version.
+func NewNamingManifest(version string) *NamingManifest {
+	return &NamingManifest{
+		Version:      version,
+		Definitions:  make(map[string]string),
+		OperationIDs: make(map[string]string),
+	}
+}
+
+// LoadNamingManifest reads the manifest at path. A missing file gives an
+// empty manifest, since it is written for the first time by the first run.
+func LoadNamingManifest(path, version string) (*NamingManifest, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewNamingManifest(version), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read naming manifest: %v", err)
+	}
+
+	m := NewNamingManifest(version)
+	if err := json.Unmarshal(content, m); err != nil {
+		return nil, fmt.Errorf("failed to parse naming manifest %s: %v", path, err)
+	}
+	if m.Version != version {
+		return nil, fmt.Errorf("naming manifest %s was recorded with naming stability %q, not %q", path, m.Version, version)
+	}
+	if m.Definitions == nil {
+		m.Definitions = make(map[string]string)
+	}
+	if m.OperationIDs == nil {
+		m.OperationIDs = make(map[string]string)
+	}
+	return m, nil
+}
+
+// Marshal renders the manifest as indented JSON with sorted keys.
+func (m *NamingManifest) Marshal() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// PinDefinitionNames replaces the derived definition names of mapping, a
+// mapping from fully qualified names to definition names, by the recorded
+// ones, and records the others. A derived name which is already recorded for
+// another proto gets a numeric suffix, so names are never reused. Entries of
+// protos which don't exist anymore are kept for the same reason.
+func (m *NamingManifest) PinDefinitionNames(mapping map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fqns := make([]string, 0, len(mapping))
+	for fqn := range mapping {
+		fqns = append(fqns, fqn)
+	}
+	sort.Strings(fqns)
+
+	taken := make(map[string]string, len(m.Definitions))
+	for fqn, name := range m.Definitions {
+		taken[name] = fqn
+	}
+	for _, fqn := range fqns {
+		if name, ok := m.Definitions[fqn]; ok {
+			mapping[fqn] = name
+		}
+	}
+	for _, fqn := range fqns {
+		if _, ok := m.Definitions[fqn]; ok {
+			continue
+		}
+		name := mapping[fqn]
+		for i := 2; ; i++ {
+			owner, ok := taken[name]
+			if !ok || owner == fqn {
+				break
+			}
+			name = fmt.Sprintf("%s%d", mapping[fqn], i)
+		}
+		mapping[fqn] = name
+		taken[name] = fqn
+		m.Definitions[fqn] = name
+	}
+}
+
+// PinOperationID returns the operationId recorded for key, or records and
+// returns derived if there is none.
+func (m *NamingManifest) PinOperationID(key, derived string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id, ok := m.OperationIDs[key]; ok {
+		return id
+	}
+	m.OperationIDs[key] = derived
+	return derived
+}
+
+// OperationKey identifies the binding of a method in the naming manifest,
+// e.g. "pkg.Service.Method" for the first binding and "pkg.Service.Method#2"
+// for the second one.
+func OperationKey(meth *Method, bindingIndex int) string {
+	key := meth.FQMN()
+	if len(key) > 0 && key[0] == '.' {
+		key = key[1:]
+	}
+	if bindingIndex != 0 {
+		key = fmt.Sprintf("%s#%d", key, bindingIndex+1)
+	}
+	return key
+}
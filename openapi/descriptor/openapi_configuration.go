@@ -1,6 +1,7 @@
 package descriptor
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 
@@ -9,10 +10,19 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
-func loadOpenAPIConfigFromYAML(yamlFileContents []byte, yamlSourceLogName string) (*openapiconfig.OpenAPIConfig, error) {
+// responseOverridesKey is the section of the OpenAPI Configuration which isn't
+// part of OpenAPIConfig, see responseOverridesConfig.
+const responseOverridesKey = "response_overrides"
+
+func loadOpenAPIConfigFromYAML(yamlFileContents []byte, yamlSourceLogName string) (*openapiconfig.OpenAPIConfig, *responseOverridesConfig, error) {
 	jsonContents, err := yaml.YAMLToJSON(yamlFileContents)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert OpenAPI Configuration from YAML in '%v' to JSON: %v", yamlSourceLogName, err)
+		return nil, nil, fmt.Errorf("failed to convert OpenAPI Configuration from YAML in '%v' to JSON: %v", yamlSourceLogName, err)
+	}
+
+	jsonContents, overrides, err := splitResponseOverrides(jsonContents)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse response overrides from YAML in '%v': %v", yamlSourceLogName, err)
 	}
 
 	// Reject unknown fields because OpenAPIConfig is only used here
@@ -22,10 +32,35 @@ func loadOpenAPIConfigFromYAML(yamlFileContents []byte, yamlSourceLogName string
 
 	openapiConfiguration := openapiconfig.OpenAPIConfig{}
 	if err := unmarshaler.Unmarshal(jsonContents, &openapiConfiguration); err != nil {
-		return nil, fmt.Errorf("failed to parse gRPC API Configuration from YAML in '%v': %v", yamlSourceLogName, err)
+		return nil, nil, fmt.Errorf("failed to parse gRPC API Configuration from YAML in '%v': %v", yamlSourceLogName, err)
+	}
+
+	return &openapiConfiguration, overrides, nil
+}
+
+// splitResponseOverrides removes the response overrides from the JSON form of
+// the configuration, so that the rest can be parsed as OpenAPIConfig.
+func splitResponseOverrides(jsonContents []byte) ([]byte, *responseOverridesConfig, error) {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(jsonContents, &sections); err != nil || sections == nil {
+		// left for protojson to report
+		return jsonContents, nil, nil
+	}
+	raw, ok := sections[responseOverridesKey]
+	if !ok {
+		return jsonContents, nil, nil
 	}
+	delete(sections, responseOverridesKey)
 
-	return &openapiConfiguration, nil
+	var overrides responseOverridesConfig
+	if err := json.Unmarshal(raw, &overrides); err != nil {
+		return nil, nil, err
+	}
+	rest, err := json.Marshal(sections)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rest, &overrides, nil
 }
 
 func registerOpenAPIOptions(registry *Registry, openAPIConfig *openapiconfig.OpenAPIConfig, yamlSourceLogName string) error {
@@ -41,7 +76,7 @@ func registerOpenAPIOptions(registry *Registry, openAPIConfig *openapiconfig.Ope
 }
 
 // LoadOpenAPIConfigFromYAML loads an  OpenAPI Configuration from the given YAML file
-// and registers the OpenAPI options the given registry.
+// and registers the OpenAPI options and response overrides in the given registry.
 // This must be done after loading the proto file.
 func (r *Registry) LoadOpenAPIConfigFromYAML(yamlFile string) error {
 	yamlFileContents, err := ioutil.ReadFile(yamlFile)
@@ -49,10 +84,13 @@ func (r *Registry) LoadOpenAPIConfigFromYAML(yamlFile string) error {
 		return fmt.Errorf("failed to read gRPC API Configuration Description from '%v': %v", yamlFile, err)
 	}
 
-	config, err := loadOpenAPIConfigFromYAML(yamlFileContents, yamlFile)
+	config, overrides, err := loadOpenAPIConfigFromYAML(yamlFileContents, yamlFile)
 	if err != nil {
 		return err
 	}
 
+	if err := registerResponseOverrides(r, overrides, yamlFile); err != nil {
+		return err
+	}
 	return registerOpenAPIOptions(r, config, yamlFile)
 }
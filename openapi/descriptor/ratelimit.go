@@ -0,0 +1,92 @@
+package descriptor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// RateLimit is the rate limit of a service or method, rendered as the
+// x-ratelimit extension of its operations.
+type RateLimit struct {
+	RequestsPerMinute uint32 `json:"requestsPerMinute"`
+	Burst             uint32 `json:"burst,omitempty"`
+}
+
+// rateLimitConfig is the YAML configuration of rate limits, e.g.
+//
+//	services:
+//	  acme.users.v1.UserService:
+//	    requestsPerMinute: 600
+//	    burst: 50
+//	methods:
+//	  acme.users.v1.UserService.CreateUser:
+//	    requestsPerMinute: 60
+//
+// The limit of a method replaces the limit of its service.
+type rateLimitConfig struct {
+	Services map[string]RateLimit `json:"services"`
+	Methods  map[string]RateLimit `json:"methods"`
+}
+
+// LoadRateLimitConfigFromYAML loads the rate limits of services and methods
+// from the given YAML file and registers them in the registry.
+// This must be done after loading the proto file.
+func (r *Registry) LoadRateLimitConfigFromYAML(yamlFile string) error {
+	yamlFileContents, err := ioutil.ReadFile(yamlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read rate limit configuration from '%v': %v", yamlFile, err)
+	}
+
+	var config rateLimitConfig
+	if err := yaml.Unmarshal(yamlFileContents, &config); err != nil {
+		return fmt.Errorf("failed to parse rate limit configuration from YAML in '%v': %v", yamlFile, err)
+	}
+
+	services, methods := r.serviceAndMethodNames()
+	r.rateLimits = make(map[string]RateLimit, len(config.Services)+len(config.Methods))
+	for kind, limits := range map[string]map[string]RateLimit{"service": config.Services, "method": config.Methods} {
+		known := services
+		if kind == "method" {
+			known = methods
+		}
+		for name, limit := range limits {
+			fqn := "." + strings.TrimPrefix(name, ".")
+			if _, ok := known[fqn]; !ok {
+				return fmt.Errorf("invalid rate limit in %s: no such %s %s", yamlFile, kind, name)
+			}
+			if limit.RequestsPerMinute == 0 {
+				return fmt.Errorf("invalid rate limit in %s: %s needs requestsPerMinute", yamlFile, name)
+			}
+			r.rateLimits[fqn] = limit
+		}
+	}
+	return nil
+}
+
+// GetRateLimit returns the rate limit of a method, falling back to the rate
+// limit of its service.
+func (r *Registry) GetRateLimit(meth *Method) (RateLimit, bool) {
+	if limit, ok := r.rateLimits[meth.FQMN()]; ok {
+		return limit, true
+	}
+	limit, ok := r.rateLimits[meth.Service.FQSN()]
+	return limit, ok
+}
+
+// serviceAndMethodNames returns the fully qualified names of the loaded
+// services and methods.
+func (r *Registry) serviceAndMethodNames() (services, methods map[string]struct{}) {
+	services, methods = make(map[string]struct{}), make(map[string]struct{})
+	for _, f := range r.files {
+		for _, svc := range f.Services {
+			services[svc.FQSN()] = struct{}{}
+			for _, m := range svc.Methods {
+				methods[m.FQMN()] = struct{}{}
+			}
+		}
+	}
+	return services, methods
+}
@@ -3,6 +3,7 @@ package descriptor
 import (
 	"fmt"
 	"github.com/jhump/protoreflect/desc"
+	"regexp"
 	"strings"
 
 	"github.com/golang/glog"
@@ -78,6 +79,10 @@ type Registry struct {
 	// repeatedPathParamSeparator specifies how path parameter repeated fields are separated
 	repeatedPathParamSeparator repeatedFieldSeparator
 
+	// fieldPathCommentsOrder specifies whether and in which order the comments of all
+	// fields along a body or response_body field path are merged into the description.
+	fieldPathCommentsOrder string
+
 	// useJSONNamesForFields if true json tag Name is used for generating fields in OpenAPI definitions,
 	// otherwise the original proto Name is used. It's helpful for synchronizing the OpenAPI definition
 	// with gRPC-Gateway response, if it uses json tags for marshaling.
@@ -129,8 +134,51 @@ type Registry struct {
 	// RPC methods that have no HttpRule annotation.
 	generateUnboundMethods bool
 
+	// generateBodyExamples causes the generator to attach an example, built from
+	// default values and field options, to request bodies without an explicit one.
+	generateBodyExamples bool
+
+	// emitGRPCWebPaths causes the generator to document the raw gRPC-Web
+	// endpoint of every method in addition to its HTTP bindings.
+	emitGRPCWebPaths bool
+
+	// responseEnvelope is the name of the built-in envelope all success
+	// responses are wrapped in, see ResponseEnvelopes.
+	responseEnvelope string
+
 	// omitPackageDoc, if false, causes a package comment to be included in the generated code.
 	omitPackageDoc bool
+
+	// lenientLoad causes Load to skip files that fail to load instead of
+	// aborting, so that the remaining files can still be generated.
+	lenientLoad bool
+
+	// unboundMethods is the list of loaded RPC methods which have no HttpRule annotation.
+	unboundMethods []UnboundMethod
+
+	// namingStability is the naming stability version, or empty if names are
+	// derived by the current tool version without guarantees.
+	namingStability string
+
+	// packagePathSegments is the number of proto package segments, ending with
+	// the version segment, which prefix synthesized and namespaced paths.
+	packagePathSegments int
+
+	// constraints is a mapping from fully-qualified message Name to its cross-field constraints
+	constraints map[string][]Constraint
+
+	// titles is a mapping from fully-qualified message, enum and field Name to localized title
+	titles map[string]string
+
+	// rateLimits is a mapping from fully-qualified service or method Name to its rate limit
+	rateLimits map[string]RateLimit
+
+	// responseOverrides is a mapping from fully-qualified service or method Name to its response override
+	responseOverrides map[string]ResponseOverride
+
+	// namingManifest records the definition names and operationIds handed out
+	// in naming stability mode.
+	namingManifest *NamingManifest
 }
 
 func (r *Registry) Schema() string {
@@ -202,12 +250,12 @@ func (r *Registry) load(gen []*desc.FileDescriptor) error {
 			continue
 		}
 
-		for _,fd := range  f.GetDependencies() {
-			fdPath := fd.GetFile().GetName()
-			r.loadFile(fdPath, fd)
+		if err := r.loadFileWithDependencies(f); err != nil {
+			if !r.lenientLoad {
+				return err
+			}
+			glog.Warningf("Skipping %s: %v", filePath, err)
 		}
-
-		r.loadFile(filePath, f)
 	}
 
 	for _, f := range gen {
@@ -216,16 +264,71 @@ func (r *Registry) load(gen []*desc.FileDescriptor) error {
 			continue
 		}
 
-
-		file := r.files[filePath]
+		file, ok := r.files[filePath]
+		if !ok {
+			// only happens in lenient mode, the file failed to load above
+			continue
+		}
 		if err := r.loadServices(file); err != nil {
-			return err
+			err = fmt.Errorf("failed to load services from %s: %v", filePath, err)
+			if !r.lenientLoad {
+				return err
+			}
+			glog.Warningf("Skipping %s: %v", filePath, err)
+			delete(r.files, filePath)
+		}
+	}
+
+	return nil
+}
+
+// loadFileWithDependencies loads "file" after the transitive closure of its
+// dependencies, so that messages from files without services can still be
+// resolved. Files which are already loaded are skipped.
+func (r *Registry) loadFileWithDependencies(file *desc.FileDescriptor) error {
+	filePath := file.GetName()
+	if _, ok := r.files[filePath]; ok {
+		return nil
+	}
+
+	for _, dep := range file.GetDependencies() {
+		if err := r.loadFileWithDependencies(dep); err != nil {
+			return fmt.Errorf("failed to load %s: dependency %s: %v", filePath, dep.GetName(), err)
 		}
 	}
 
+	if err := r.checkDuplicateRegistration(file); err != nil {
+		return fmt.Errorf("failed to load %s: %v", filePath, err)
+	}
+
+	r.loadFile(filePath, file)
 	return nil
 }
 
+// checkDuplicateRegistration returns an error if a message or enum of "file"
+// has already been registered by another file. It runs before anything of
+// "file" is registered, so a failing file leaves the registry untouched.
+func (r *Registry) checkDuplicateRegistration(file *desc.FileDescriptor) error {
+	var check func(msgs []*desc.MessageDescriptor, enums []*desc.EnumDescriptor) error
+	check = func(msgs []*desc.MessageDescriptor, enums []*desc.EnumDescriptor) error {
+		for _, ed := range enums {
+			if e, ok := r.enums["."+ed.GetFullyQualifiedName()]; ok {
+				return fmt.Errorf("duplicate registration of enum %s, already registered by %s", ed.GetFullyQualifiedName(), e.File.GetName())
+			}
+		}
+		for _, md := range msgs {
+			if m, ok := r.msgs["."+md.GetFullyQualifiedName()]; ok {
+				return fmt.Errorf("duplicate registration of message %s, already registered by %s", md.GetFullyQualifiedName(), m.File.GetName())
+			}
+			if err := check(md.GetNestedMessageTypes(), md.GetNestedEnumTypes()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return check(file.GetMessageTypes(), file.GetEnumTypes())
+}
+
 // loadFile loads messages, enumerations and fields from "file".
 // It does not loads services and methods in "file".  You need to call
 // loadServices after loadFiles is called for all files to load services and methods.
@@ -402,6 +505,12 @@ func (r *Registry) UnboundExternalHTTPRules() []string {
 	return missingMethods
 }
 
+// UnboundMethods returns the RPC methods which have neither a HttpRule
+// annotation nor an external HttpRule, in the order they were loaded.
+func (r *Registry) UnboundMethods() []UnboundMethod {
+	return r.unboundMethods
+}
+
 // AddPkgMap adds a mapping from a .proto file to proto package Name.
 func (r *Registry) AddPkgMap(file, protoPkg string) {
 	r.pkgMap[file] = protoPkg
@@ -412,6 +521,17 @@ func (r *Registry) SetPrefix(prefix string) {
 	r.prefix = prefix
 }
 
+// SetLenientLoad controls whether Load skips files that fail to load
+// instead of returning the first error.
+func (r *Registry) SetLenientLoad(lenient bool) {
+	r.lenientLoad = lenient
+}
+
+// IsLenientLoad returns lenientLoad
+func (r *Registry) IsLenientLoad() bool {
+	return r.lenientLoad
+}
+
 // SetStandalone registers standalone flag to control package prefix
 func (r *Registry) SetStandalone(standalone bool) {
 	r.standalone = standalone
@@ -530,6 +650,68 @@ func (r *Registry) SetRepeatedPathParamSeparator(name string) error {
 	return nil
 }
 
+// GetFieldPathCommentsOrder returns how the comments along a body or
+// response_body field path are merged. I.e. '', 'outer_first' or 'inner_first'
+func (r *Registry) GetFieldPathCommentsOrder() string {
+	return r.fieldPathCommentsOrder
+}
+
+// SetFieldPathCommentsOrder sets how the comments along a body or response_body
+// field path are merged. Allowed names are '' (only the comments of the last field
+// are used), 'outer_first' and 'inner_first'.
+func (r *Registry) SetFieldPathCommentsOrder(order string) error {
+	switch order {
+	case "", "outer_first", "inner_first":
+	default:
+		return fmt.Errorf("unknown field path comments order: %s", order)
+	}
+	r.fieldPathCommentsOrder = order
+	return nil
+}
+
+// GetNamingStability returns the naming stability version, or an empty
+// string if naming stability is disabled.
+func (r *Registry) GetNamingStability() string {
+	return r.namingStability
+}
+
+// SetNamingStability enables the naming stability mode of the given version,
+// in which definition names and operationIds are recorded in the naming
+// manifest and reused by later runs. Allowed versions are '' (disabled) and 'v1'.
+func (r *Registry) SetNamingStability(version string) error {
+	switch version {
+	case "":
+		r.namingManifest = nil
+	case NamingStabilityV1:
+		if r.namingManifest == nil || r.namingManifest.Version != version {
+			r.namingManifest = NewNamingManifest(version)
+		}
+	default:
+		return fmt.Errorf("unknown naming stability version: %s", version)
+	}
+	r.namingStability = version
+	return nil
+}
+
+// GetNamingManifest returns the naming manifest, or nil if naming stability
+// is disabled.
+func (r *Registry) GetNamingManifest() *NamingManifest {
+	return r.namingManifest
+}
+
+// SetNamingManifest sets the manifest of previously recorded names. It must
+// have been recorded with the naming stability version of the registry.
+func (r *Registry) SetNamingManifest(m *NamingManifest) error {
+	if r.namingStability == "" {
+		return fmt.Errorf("a naming manifest requires a naming stability version")
+	}
+	if m.Version != r.namingStability {
+		return fmt.Errorf("naming manifest was recorded with naming stability %q, not %q", m.Version, r.namingStability)
+	}
+	r.namingManifest = m
+	return nil
+}
+
 // SetUseJSONNamesForFields sets useJSONNamesForFields
 func (r *Registry) SetUseJSONNamesForFields(use bool) {
 	r.useJSONNamesForFields = use
@@ -605,6 +787,42 @@ func (r *Registry) SetGenerateUnboundMethods(generate bool) {
 	r.generateUnboundMethods = generate
 }
 
+// SetGenerateBodyExamples sets generateBodyExamples
+func (r *Registry) SetGenerateBodyExamples(generate bool) {
+	r.generateBodyExamples = generate
+}
+
+// IsGenerateBodyExamples returns generateBodyExamples
+func (r *Registry) IsGenerateBodyExamples() bool {
+	return r.generateBodyExamples
+}
+
+// SetEmitGRPCWebPaths sets emitGRPCWebPaths
+func (r *Registry) SetEmitGRPCWebPaths(emit bool) {
+	r.emitGRPCWebPaths = emit
+}
+
+// IsEmitGRPCWebPaths returns emitGRPCWebPaths
+func (r *Registry) IsEmitGRPCWebPaths() bool {
+	return r.emitGRPCWebPaths
+}
+
+// GetResponseEnvelope returns the name of the envelope success responses are
+// wrapped in, or an empty string if they aren't wrapped.
+func (r *Registry) GetResponseEnvelope() string {
+	return r.responseEnvelope
+}
+
+// SetResponseEnvelope sets the envelope success responses are wrapped in.
+// Allowed names are '' (no envelope) and the keys of ResponseEnvelopes.
+func (r *Registry) SetResponseEnvelope(name string) error {
+	if _, ok := ResponseEnvelopes[name]; name != "" && !ok {
+		return fmt.Errorf("unknown response envelope: %s", name)
+	}
+	r.responseEnvelope = name
+	return nil
+}
+
 // SetOmitPackageDoc controls whether the generated code contains a package comment (if set to false, it will contain one)
 func (r *Registry) SetOmitPackageDoc(omit bool) {
 	r.omitPackageDoc = omit
@@ -722,4 +940,47 @@ func (r *Registry)GetNamespace ()string {
 	return r.namespace
 }
 
+// SetPackagePathSegments sets how many proto package segments, ending with the
+// version segment, are used as path prefix for unbound methods and namespaced
+// paths. 0 disables the prefix.
+func (r *Registry) SetPackagePathSegments(n int) error {
+	if n < 0 {
+		return fmt.Errorf("package path segments must not be negative: %d", n)
+	}
+	r.packagePathSegments = n
+	return nil
+}
+
+// GetPackagePathSegments returns packagePathSegments
+func (r *Registry) GetPackagePathSegments() int {
+	return r.packagePathSegments
+}
+
+// packageVersionRegexp matches version segments of proto packages, e.g. v2 or v1beta1.
+var packageVersionRegexp = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]*)?$`)
+
+// PackagePathPrefix returns the path prefix derived from a proto package,
+// e.g. "/billing/v2" for "acme.billing.v2" with 2 package path segments. It
+// returns an empty string if the prefix is disabled or the package has no
+// version segment.
+func (r *Registry) PackagePathPrefix(pkg string) string {
+	if r.packagePathSegments == 0 || pkg == "" {
+		return ""
+	}
+	parts := strings.Split(pkg, ".")
+	end := len(parts) - 1
+	for end >= 0 && !packageVersionRegexp.MatchString(parts[end]) {
+		end--
+	}
+	if end < 0 {
+		glog.V(1).Infof("package %s has no version segment, no path prefix is derived", pkg)
+		return ""
+	}
+	start := end + 1 - r.packagePathSegments
+	if start < 0 {
+		start = 0
+	}
+	return "/" + strings.Join(parts[start:end+1], "/")
+}
+
 
@@ -0,0 +1,88 @@
+package descriptor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ResponseRef is the "$ref" which refers, in the schema of a response
+// override, to the response schema the method has without the override.
+const ResponseRef = "$response"
+
+// ResponseOverride replaces the schema of the success response of methods,
+// e.g. to wrap it in the envelope a gateway adds:
+//
+//	schema:
+//	  type: object
+//	  properties:
+//	    code: {type: integer, format: int32}
+//	    msg: {type: string}
+//	    data: {$ref: $response}
+type ResponseOverride struct {
+	// Description replaces the description of the response if it is set.
+	Description string `json:"description,omitempty"`
+	// Schema is the OpenAPI schema of the response, in which ResponseRef
+	// refers to the original schema.
+	Schema json.RawMessage `json:"schema"`
+}
+
+// responseOverridesConfig is the response_overrides section of the OpenAPI
+// configuration, e.g.
+//
+//	response_overrides:
+//	  services:
+//	    acme.users.v1.UserService:
+//	      schema: ...
+//	  methods:
+//	    acme.users.v1.UserService.GetUser:
+//	      schema: ...
+//
+// The override of a method replaces the override of its service.
+type responseOverridesConfig struct {
+	Services map[string]ResponseOverride `json:"services"`
+	Methods  map[string]ResponseOverride `json:"methods"`
+}
+
+func registerResponseOverrides(r *Registry, config *responseOverridesConfig, yamlSourceLogName string) error {
+	if config == nil {
+		return nil
+	}
+	services, methods := r.serviceAndMethodNames()
+	r.responseOverrides = make(map[string]ResponseOverride, len(config.Services)+len(config.Methods))
+	for kind, overrides := range map[string]map[string]ResponseOverride{"service": config.Services, "method": config.Methods} {
+		known := services
+		if kind == "method" {
+			known = methods
+		}
+		for name, override := range overrides {
+			fqn := "." + strings.TrimPrefix(name, ".")
+			if _, ok := known[fqn]; !ok {
+				return fmt.Errorf("invalid response override in %s: no such %s %s", yamlSourceLogName, kind, name)
+			}
+			if len(override.Schema) == 0 {
+				return fmt.Errorf("invalid response override in %s: %s needs a schema", yamlSourceLogName, name)
+			}
+			r.responseOverrides[fqn] = override
+		}
+	}
+	return nil
+}
+
+// GetResponseOverride returns the response override of a method, falling back
+// to the response override of its service.
+func (r *Registry) GetResponseOverride(meth *Method) (ResponseOverride, bool) {
+	if override, ok := r.responseOverrides[meth.FQMN()]; ok {
+		return override, true
+	}
+	override, ok := r.responseOverrides[meth.Service.FQSN()]
+	return override, ok
+}
+
+// ResponseEnvelopes maps the names of the built-in response envelopes to the
+// shape of their JSON.
+var ResponseEnvelopes = map[string]string{
+	"code-msg-data":     `{"code": 0, "msg": "...", "data": <response>}`,
+	"code-message-data": `{"code": 0, "message": "...", "data": <response>}`,
+	"jsonapi":           `{"data": <response>, "meta": {...}, "links": {...}}`,
+}
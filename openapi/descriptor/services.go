@@ -35,9 +35,10 @@ func (r *Registry) loadServices(file *File) error {
 			if opts != nil {
 				optsList = append(optsList, opts)
 			}
-			if len(optsList) == 0 {
+			unbound := len(optsList) == 0
+			if unbound {
 				if r.generateUnboundMethods {
-					defaultOpts, err := defaultAPIOptions(svc, md)
+					defaultOpts, err := r.defaultAPIOptions(svc, md)
 					if err != nil {
 						glog.Errorf("Failed to generate default HttpRule from %s.%s: %v", svc.GetName(), md.GetName(), err)
 						return err
@@ -55,6 +56,12 @@ func (r *Registry) loadServices(file *File) error {
 			if err != nil {
 				return err
 			}
+			if unbound {
+				r.unboundMethods = append(r.unboundMethods, UnboundMethod{
+					Method:    meth,
+					AutoBound: r.generateUnboundMethods,
+				})
+			}
 			svc.Methods = append(svc.Methods, meth)
 		}
 		if len(svc.Methods) == 0 {
@@ -70,11 +77,11 @@ func (r *Registry) loadServices(file *File) error {
 func (r *Registry) newMethod(svc *Service, md *descriptorpb.MethodDescriptorProto, optsList []*options.HttpRule) (*Method, error) {
 	requestType, err := r.LookupMsg(svc.File.GetPackage(), md.GetInputType())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s.%s: cannot resolve request type %s: %v", svc.GetName(), md.GetName(), md.GetInputType(), err)
 	}
 	responseType, err := r.LookupMsg(svc.File.GetPackage(), md.GetOutputType())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%s.%s: cannot resolve response type %s: %v", svc.GetName(), md.GetName(), md.GetOutputType(), err)
 	}
 	meth := &Method{
 		Service:               svc,
@@ -212,10 +219,16 @@ func extractAPIOptions(meth *descriptorpb.MethodDescriptorProto) (*options.HttpR
 	return opts, nil
 }
 
-func defaultAPIOptions(svc *Service, md *descriptorpb.MethodDescriptorProto) (*options.HttpRule, error) {
+func (r *Registry) defaultAPIOptions(svc *Service, md *descriptorpb.MethodDescriptorProto) (*options.HttpRule, error) {
 	// FQSN prefixes the service's full Name with a '.', e.g.: '.example.ExampleService'
 	fqsn := strings.TrimPrefix(svc.FQSN(), ".")
 
+	// With a path prefix derived from the package, the prefix carries the
+	// version, e.g.: '/billing/v2/BillingService/Method'
+	if prefix := r.PackagePathPrefix(svc.File.GetPackage()); prefix != "" {
+		fqsn = strings.TrimPrefix(prefix, "/") + "/" + svc.GetName()
+	}
+
 	// This generates an HttpRule that matches the gRPC mapping to HTTP/2 described in
 	// https://github.com/grpc/grpc/blob/master/doc/PROTOCOL-HTTP2.md#requests
 	// i.e.:
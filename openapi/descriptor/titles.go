@@ -0,0 +1,63 @@
+package descriptor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/glog"
+)
+
+// LoadTitlesFromYAML loads a translation map from the given YAML file, which
+// maps fully qualified message, enum and field names to localized titles, e.g.
+//
+//	acme.users.v1.User: 用户
+//	acme.users.v1.User.email: 邮箱
+//
+// The titles are rendered as the title of the schemas and properties, the
+// names on the wire are kept. This must be done after loading the proto file.
+func (r *Registry) LoadTitlesFromYAML(yamlFile string) error {
+	yamlFileContents, err := ioutil.ReadFile(yamlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read titles from '%v': %v", yamlFile, err)
+	}
+
+	var titles map[string]string
+	if err := yaml.Unmarshal(yamlFileContents, &titles); err != nil {
+		return fmt.Errorf("failed to parse titles from YAML in '%v': %v", yamlFile, err)
+	}
+
+	r.titles = make(map[string]string, len(titles))
+	for name, title := range titles {
+		fqn := "." + strings.TrimPrefix(name, ".")
+		if !r.hasQualifiedName(fqn) {
+			glog.Warningf("%s: no message, enum or field %s, its title is ignored", yamlFile, name)
+		}
+		r.titles[fqn] = title
+	}
+	return nil
+}
+
+// GetTitle returns the localized title of the message, enum or field with the
+// given fully qualified name.
+func (r *Registry) GetTitle(fqn string) (string, bool) {
+	title, ok := r.titles[fqn]
+	return title, ok
+}
+
+// hasQualifiedName reports whether fqn names a loaded message, enum or field.
+func (r *Registry) hasQualifiedName(fqn string) bool {
+	if _, ok := r.msgs[fqn]; ok {
+		return true
+	}
+	if _, ok := r.enums[fqn]; ok {
+		return true
+	}
+	i := strings.LastIndex(fqn, ".")
+	if i <= 0 {
+		return false
+	}
+	msg, ok := r.msgs[fqn[:i]]
+	return ok && hasField(msg, fqn[i+1:])
+}
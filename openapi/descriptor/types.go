@@ -43,6 +43,18 @@ type ResponseFile struct {
 	*pluginpb.CodeGeneratorResponse_File
 	// GoPkg is the Go package of the generated file.
 	GoPkg GoPackage
+	// Extensions lists the vendor extensions of the generated document.
+	Extensions []ExtensionUse
+}
+
+// ExtensionUse is a vendor extension in a generated document.
+type ExtensionUse struct {
+	// Location is the JSON pointer of the object carrying the extension, e.g. "/paths/~1v1~1users/get".
+	Location string
+	// Key is the extension key, e.g. "x-ratelimit".
+	Key string
+	// Source tells where the extension comes from, e.g. "proto option" or "config file".
+	Source string
 }
 
 // File wraps descriptorpb.FileDescriptorProto for richer features.
@@ -216,6 +228,15 @@ func (m *Method) FQMN() string {
 	return strings.Join(components, ".")
 }
 
+// UnboundMethod describes a method which has no HttpRule annotation.
+type UnboundMethod struct {
+	// Method is the unannotated method.
+	Method *Method
+	// AutoBound is true if a default HttpRule was generated for the method,
+	// false if the method was skipped.
+	AutoBound bool
+}
+
 // Binding describes how an HTTP endpoint is bound to a gRPC method.
 type Binding struct {
 	// Method is the method which the endpoint is bound to.
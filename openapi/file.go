@@ -2,13 +2,16 @@ package openapi
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/golang/protobuf/proto"
 	descpb "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/jhump/protoreflect/desc"
 	"io/ioutil"
 )
 
-//加载protoset
+// LoadProtosetFile loads every file of the protoset, including files that
+// only declare messages or enums, so that the registry keeps the full
+// dependency closure. Callers decide which of them become generation targets.
 func LoadProtosetFile(filepath string) ([]*desc.FileDescriptor, error) {
 	bytes, err := ioutil.ReadFile(filepath)
 	if err != nil {
@@ -20,16 +23,48 @@ func LoadProtosetFile(filepath string) ([]*desc.FileDescriptor, error) {
 		return nil, err
 	}
 
-	test, err := desc.CreateFileDescriptorsFromSet(&fileSet)
+	if err := checkProtosetDependencies(&fileSet); err != nil {
+		return nil, fmt.Errorf("invalid protoset %s: %v", filepath, err)
+	}
+
+	fds, err := desc.CreateFileDescriptorsFromSet(&fileSet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protoset %s: %v", filepath, err)
+	}
+
+	// keep the order of the protoset so that the output is stable
 	var FileDs []*desc.FileDescriptor
-	for _, val := range test {
-		if len(val.GetServices()) > 0 {
-			FileDs = append(FileDs, val.GetFile())
+	for _, fd := range fileSet.GetFile() {
+		if val, ok := fds[fd.GetName()]; ok {
+			FileDs = append(FileDs, val)
 		}
 	}
 	return FileDs, nil
 }
 
+// checkProtosetDependencies makes sure every import of every file is part of
+// the set, and reports the first one which is not.
+func checkProtosetDependencies(fileSet *descpb.FileDescriptorSet) error {
+	names := make(map[string]struct{}, len(fileSet.GetFile()))
+	for _, fd := range fileSet.GetFile() {
+		names[fd.GetName()] = struct{}{}
+	}
+	for _, fd := range fileSet.GetFile() {
+		for _, dep := range fd.GetDependency() {
+			if _, ok := names[dep]; !ok {
+				return fmt.Errorf("%s imports %s, which is missing from the set (was it built with --include_imports?)", fd.GetName(), dep)
+			}
+		}
+	}
+	return nil
+}
+
+// HasServices reports whether fd declares at least one service and thus
+// should be a generation target.
+func HasServices(fd *desc.FileDescriptor) bool {
+	return len(fd.GetServices()) > 0
+}
+
 func WriteSwaggerJsonToFile(swagger *openapiSwaggerObject)error{
 	v, err := json.MarshalIndent(swagger, "", "    ")
 	if err != nil {
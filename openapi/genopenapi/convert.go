@@ -0,0 +1,468 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// Convert upgrades an OpenAPI v2 document to OpenAPI 3.0 or 3.1, depending on
+// version, which is one of "3.0" or "3.1". The document is read into the model
+// used by the generator, so previously generated documents convert without
+// loss, and fields which the model can't represent are dropped.
+//
+// Body and formData parameters become request bodies, using the media types
+// the operation or the document consumes, and response schemas are rendered
+// for each media type the operation produces. References are moved to
+// components.
+func Convert(in []byte, version string) ([]byte, error) {
+	c := &v3Converter{}
+	switch version {
+	case "3.0", "3.0.3":
+		c.version = "3.0.3"
+	case "3.1", "3.1.0":
+		c.version = "3.1.0"
+		c.v31 = true
+	default:
+		return nil, fmt.Errorf("unsupported target version %q, must be 3.0 or 3.1", version)
+	}
+
+	swagger, err := decodeOpenAPI(in)
+	if err != nil {
+		return nil, err
+	}
+	return formatOpenAPI(c.document(swagger))
+}
+
+type v3Converter struct {
+	version string
+	v31     bool
+
+	// swagger is the document being converted, used to resolve references
+	// to global parameters.
+	swagger *openapiSwaggerObject
+}
+
+func (c *v3Converter) document(s *openapiSwaggerObject) *openapiV3Document {
+	c.swagger = s
+	doc := &openapiV3Document{
+		OpenAPI:      c.version,
+		Info:         s.Info,
+		Servers:      v3Servers(s),
+		Tags:         s.Tags,
+		Paths:        map[string]openapiV3PathItemObject{},
+		Security:     s.Security,
+		ExternalDocs: s.ExternalDocs,
+		extensions:   s.extensions,
+	}
+
+	components := &openapiV3ComponentsObject{}
+	for name, def := range s.Definitions {
+		if components.Schemas == nil {
+			components.Schemas = map[string]openapiV3SchemaObject{}
+		}
+		components.Schemas[name] = *c.schema(def)
+	}
+	for name, param := range s.Parameters {
+		if param.In == "body" || param.In == "formData" {
+			// Referencing operations inline these as request bodies.
+			continue
+		}
+		if components.Parameters == nil {
+			components.Parameters = map[string]openapiV3ParameterObject{}
+		}
+		components.Parameters[name] = c.parameter(param)
+	}
+	for name, scheme := range s.SecurityDefinitions {
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = map[string]openapiV3SecuritySchemeObject{}
+		}
+		components.SecuritySchemes[name] = v3SecurityScheme(scheme)
+	}
+	if !reflect.DeepEqual(*components, openapiV3ComponentsObject{}) {
+		doc.Components = components
+	}
+
+	for path, item := range s.Paths {
+		doc.Paths[path] = openapiV3PathItemObject{
+			Get:    c.operation(item.Get),
+			Delete: c.operation(item.Delete),
+			Post:   c.operation(item.Post),
+			Put:    c.operation(item.Put),
+			Patch:  c.operation(item.Patch),
+		}
+	}
+	return doc
+}
+
+// v3Servers builds the servers list from the host, base path and schemes.
+func v3Servers(s *openapiSwaggerObject) []openapiV3ServerObject {
+	if s.Host == "" {
+		if s.BasePath == "" {
+			return nil
+		}
+		return []openapiV3ServerObject{{URL: s.BasePath}}
+	}
+	if len(s.Schemes) == 0 {
+		return []openapiV3ServerObject{{URL: "//" + s.Host + s.BasePath}}
+	}
+	var servers []openapiV3ServerObject
+	for _, scheme := range s.Schemes {
+		servers = append(servers, openapiV3ServerObject{URL: scheme + "://" + s.Host + s.BasePath})
+	}
+	return servers
+}
+
+func v3SecurityScheme(s openapiSecuritySchemeObject) openapiV3SecuritySchemeObject {
+	out := openapiV3SecuritySchemeObject{
+		Type:        s.Type,
+		Description: s.Description,
+		extensions:  s.extensions,
+	}
+	switch s.Type {
+	case "basic":
+		out.Type = "http"
+		out.Scheme = "basic"
+	case "apiKey":
+		out.Name = s.Name
+		out.In = s.In
+	case "oauth2":
+		scopes := s.Scopes
+		if scopes == nil {
+			scopes = openapiScopesObject{}
+		}
+		flow := &openapiV3OAuthFlowObject{Scopes: scopes}
+		out.Flows = &openapiV3OAuthFlowsObject{}
+		switch s.Flow {
+		case "implicit":
+			flow.AuthorizationURL = s.AuthorizationURL
+			out.Flows.Implicit = flow
+		case "password":
+			flow.TokenURL = s.TokenURL
+			out.Flows.Password = flow
+		case "application":
+			flow.TokenURL = s.TokenURL
+			out.Flows.ClientCredentials = flow
+		case "accessCode":
+			flow.AuthorizationURL = s.AuthorizationURL
+			flow.TokenURL = s.TokenURL
+			out.Flows.AuthorizationCode = flow
+		default:
+			glog.Warningf("unknown oauth2 flow %q, dropping it", s.Flow)
+		}
+	}
+	return out
+}
+
+func (c *v3Converter) operation(op *openapiOperationObject) *openapiV3OperationObject {
+	if op == nil {
+		return nil
+	}
+	out := &openapiV3OperationObject{
+		Summary:      op.Summary,
+		Description:  op.Description,
+		OperationID:  op.OperationID,
+		Responses:    map[string]openapiV3ResponseObject{},
+		Tags:         op.Tags,
+		Deprecated:   op.Deprecated,
+		Security:     op.Security,
+		ExternalDocs: op.ExternalDocs,
+		extensions:   op.extensions,
+	}
+
+	var formData []openapiParameterObject
+	for _, param := range op.Parameters {
+		if param.Ref != "" {
+			if resolved, ok := c.globalParameter(param.Ref); ok && (resolved.In == "body" || resolved.In == "formData") {
+				param = resolved
+			}
+		}
+		switch param.In {
+		case "body":
+			out.RequestBody = c.requestBody(param, op.Consumes)
+		case "formData":
+			formData = append(formData, param)
+		default:
+			out.Parameters = append(out.Parameters, c.parameter(param))
+		}
+	}
+	if len(formData) > 0 {
+		out.RequestBody = c.formRequestBody(formData)
+	}
+
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = c.swagger.Produces
+	}
+	for code, resp := range op.Responses {
+		out.Responses[code] = c.response(resp, produces)
+	}
+	return out
+}
+
+func (c *v3Converter) globalParameter(ref string) (openapiParameterObject, bool) {
+	name := strings.TrimPrefix(canonicalRef(ref, "parameters", nil), "#/parameters/")
+	param, ok := c.swagger.Parameters[name]
+	return param, ok
+}
+
+func (c *v3Converter) consumes(opConsumes []string) []string {
+	if len(opConsumes) > 0 {
+		return opConsumes
+	}
+	if len(c.swagger.Consumes) == 0 {
+		return []string{"application/json"}
+	}
+	return c.swagger.Consumes
+}
+
+func (c *v3Converter) requestBody(param openapiParameterObject, opConsumes []string) *openapiV3RequestBodyObject {
+	body := &openapiV3RequestBodyObject{
+		Description: param.Description,
+		Required:    param.Required,
+		Content:     map[string]openapiV3MediaTypeObject{},
+	}
+	var schema *openapiV3SchemaObject
+	if param.Schema != nil {
+		schema = c.schema(*param.Schema)
+	}
+	for _, mediaType := range c.consumes(opConsumes) {
+		body.Content[mediaType] = openapiV3MediaTypeObject{Schema: schema}
+	}
+	return body
+}
+
+// formRequestBody merges formData parameters into the properties of a single
+// request body schema.
+func (c *v3Converter) formRequestBody(params []openapiParameterObject) *openapiV3RequestBodyObject {
+	mediaType := "application/x-www-form-urlencoded"
+	schema := &openapiV3SchemaObject{Type: "object"}
+	props := openapiSchemaObjectProperties{}
+	for _, param := range params {
+		prop := c.parameter(param).Schema
+		if param.Type == "file" {
+			mediaType = "multipart/form-data"
+			prop = &openapiV3SchemaObject{Type: "string", Format: "binary"}
+		}
+		prop.Description = param.Description
+		props = append(props, keyVal{Key: param.Name, Value: *prop})
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		}
+	}
+	for _, consumes := range c.swagger.Consumes {
+		if consumes == "multipart/form-data" {
+			mediaType = consumes
+		}
+	}
+	schema.Properties = &props
+	return &openapiV3RequestBodyObject{
+		Required: len(schema.Required) > 0,
+		Content:  map[string]openapiV3MediaTypeObject{mediaType: {Schema: schema}},
+	}
+}
+
+func (c *v3Converter) parameter(p openapiParameterObject) openapiV3ParameterObject {
+	if p.Ref != "" {
+		return openapiV3ParameterObject{Ref: v3Ref(canonicalRef(p.Ref, "parameters", nil))}
+	}
+	out := openapiV3ParameterObject{
+		Name:        p.Name,
+		In:          p.In,
+		Description: p.Description,
+		Required:    p.Required || p.In == "path",
+	}
+	if p.Schema != nil {
+		out.Schema = c.schema(*p.Schema)
+		return out
+	}
+
+	schema := &openapiV3SchemaObject{
+		Type:   p.Type,
+		Format: p.Format,
+		Enum:   p.Enum,
+	}
+	if p.Default != "" {
+		schema.Default = v3Default(p.Default)
+	}
+	if p.MinItems != nil && *p.MinItems > 0 {
+		schema.MinItems = uint64(*p.MinItems)
+	}
+	if p.Items != nil {
+		schema.Items = c.schemaCore(schemaCore(*p.Items))
+	}
+	out.Schema = schema
+
+	if p.Type == "array" {
+		explode := false
+		switch p.CollectionFormat {
+		case "", "csv":
+			if p.In == "query" {
+				out.Style = "form"
+				out.Explode = &explode
+			}
+		case "multi":
+			// form and explode are the defaults for query parameters
+		case "ssv":
+			out.Style = "spaceDelimited"
+			out.Explode = &explode
+		case "pipes":
+			out.Style = "pipeDelimited"
+			out.Explode = &explode
+		default:
+			glog.Warningf("parameter %s: collection format %q has no OpenAPI 3 equivalent", p.Name, p.CollectionFormat)
+		}
+	}
+	return out
+}
+
+func (c *v3Converter) response(r openapiResponseObject, produces []string) openapiV3ResponseObject {
+	out := openapiV3ResponseObject{
+		Description: r.Description,
+		extensions:  r.extensions,
+	}
+	for name, header := range r.Headers {
+		if out.Headers == nil {
+			out.Headers = map[string]openapiV3HeaderObject{}
+		}
+		out.Headers[name] = openapiV3HeaderObject{
+			Description: header.Description,
+			Schema: &openapiV3SchemaObject{
+				Type:    header.Type,
+				Format:  header.Format,
+				Default: header.Default,
+				Pattern: header.Pattern,
+			},
+		}
+	}
+
+	content := map[string]openapiV3MediaTypeObject{}
+	if !reflect.DeepEqual(r.Schema, openapiSchemaObject{}) {
+		if len(produces) == 0 {
+			produces = []string{"application/json"}
+		}
+		schema := c.schema(r.Schema)
+		for _, mediaType := range produces {
+			content[mediaType] = openapiV3MediaTypeObject{Schema: schema}
+		}
+	}
+	mediaTypes := make([]string, 0, len(r.Examples))
+	for mediaType := range r.Examples {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+	for _, mediaType := range mediaTypes {
+		example, err := json.Marshal(r.Examples[mediaType])
+		if err != nil {
+			glog.Warningf("dropping %s example: %v", mediaType, err)
+			continue
+		}
+		media := content[mediaType]
+		media.Example = example
+		content[mediaType] = media
+	}
+	if len(content) > 0 {
+		out.Content = content
+	}
+	return out
+}
+
+func (c *v3Converter) schema(s openapiSchemaObject) *openapiV3SchemaObject {
+	out := c.schemaCore(s.schemaCore)
+	out.Description = s.Description
+	out.Title = s.Title
+	out.ExternalDocs = s.ExternalDocs
+	out.ReadOnly = s.ReadOnly
+	out.MultipleOf = s.MultipleOf
+	out.Maximum = s.Maximum
+	out.Minimum = s.Minimum
+	out.MaxLength = s.MaxLength
+	out.MinLength = s.MinLength
+	out.Pattern = s.Pattern
+	out.MaxItems = s.MaxItems
+	out.MinItems = s.MinItems
+	out.UniqueItems = s.UniqueItems
+	out.MaxProperties = s.MaxProperties
+	out.MinProperties = s.MinProperties
+	out.Required = s.Required
+	out.extensions = s.extensions
+
+	// 3.1 follows JSON Schema, where the exclusive bounds replace the
+	// inclusive ones instead of modifying them.
+	if s.ExclusiveMaximum {
+		if c.v31 {
+			out.ExclusiveMaximum = s.Maximum
+			out.Maximum = 0
+		} else {
+			out.ExclusiveMaximum = true
+		}
+	}
+	if s.ExclusiveMinimum {
+		if c.v31 {
+			out.ExclusiveMinimum = s.Minimum
+			out.Minimum = 0
+		} else {
+			out.ExclusiveMinimum = true
+		}
+	}
+
+	if s.AdditionalProperties != nil {
+		out.AdditionalProperties = c.schema(*s.AdditionalProperties)
+	}
+	if s.Properties != nil {
+		props := make(openapiSchemaObjectProperties, 0, len(*s.Properties))
+		for _, prop := range *s.Properties {
+			value := prop.Value
+			if schema, ok := prop.Value.(openapiSchemaObject); ok {
+				value = *c.schema(schema)
+			}
+			props = append(props, keyVal{Key: prop.Key, Value: value})
+		}
+		out.Properties = &props
+	}
+	return out
+}
+
+func (c *v3Converter) schemaCore(s schemaCore) *openapiV3SchemaObject {
+	out := &openapiV3SchemaObject{
+		Type:    s.Type,
+		Format:  s.Format,
+		Example: s.Example,
+		Enum:    s.Enum,
+	}
+	if s.Ref != "" {
+		out.Ref = v3Ref(canonicalRef(s.Ref, "definitions", nil))
+	}
+	if s.Default != "" {
+		out.Default = v3Default(s.Default)
+	}
+	if s.Items != nil {
+		out.Items = c.schemaCore(schemaCore(*s.Items))
+	}
+	return out
+}
+
+// v3Ref moves a local reference from its 2.0 location to components.
+func v3Ref(ref string) string {
+	for _, section := range []string{"definitions", "parameters"} {
+		if strings.HasPrefix(ref, "#/"+section+"/") {
+			target := section
+			if section == "definitions" {
+				target = "schemas"
+			}
+			return "#/components/" + target + "/" + strings.TrimPrefix(ref, "#/"+section+"/")
+		}
+	}
+	return ref
+}
+
+// v3Default renders a default value the way the 2.0 model stores it, as a
+// string.
+func v3Default(value string) json.RawMessage {
+	quoted, _ := json.Marshal(value)
+	return quoted
+}
@@ -0,0 +1,121 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const convertInput = `{
+	"swagger": "2.0",
+	"info": {"title": "users", "version": "1"},
+	"host": "api.example.com",
+	"basePath": "/api",
+	"schemes": ["https"],
+	"consumes": ["application/json"],
+	"produces": ["application/json"],
+	"paths": {
+		"/v1/users": {
+			"post": {
+				"operationId": "Create",
+				"parameters": [
+					{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/User"}},
+					{"name": "tags", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "csv"}
+				],
+				"responses": {
+					"200": {"description": "ok", "schema": {"$ref": "#/definitions/User"}, "x-cache": true}
+				}
+			}
+		}
+	},
+	"definitions": {
+		"User": {
+			"type": "object",
+			"properties": {
+				"age": {"type": "integer", "maximum": 150, "exclusiveMaximum": true, "default": "0"}
+			}
+		}
+	},
+	"securityDefinitions": {
+		"oauth": {"type": "oauth2", "flow": "application", "tokenUrl": "https://auth.example.com/token"}
+	}
+}`
+
+func TestConvert(t *testing.T) {
+	for _, spec := range []struct {
+		version     string
+		age         string
+		wantVersion string
+	}{
+		{
+			version:     "3.0",
+			age:         `{"type": "integer", "maximum": 150, "exclusiveMaximum": true, "default": "0"}`,
+			wantVersion: "3.0.3",
+		},
+		{
+			version:     "3.1",
+			age:         `{"type": "integer", "exclusiveMaximum": 150, "default": "0"}`,
+			wantVersion: "3.1.0",
+		},
+	} {
+		t.Run(spec.version, func(t *testing.T) {
+			expected := `{
+				"openapi": "` + spec.wantVersion + `",
+				"info": {"title": "users", "version": "1"},
+				"servers": [{"url": "https://api.example.com/api"}],
+				"paths": {
+					"/v1/users": {
+						"post": {
+							"operationId": "Create",
+							"parameters": [
+								{"name": "tags", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "string"}}}
+							],
+							"requestBody": {
+								"required": true,
+								"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}
+							},
+							"responses": {
+								"200": {
+									"description": "ok",
+									"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}},
+									"x-cache": true
+								}
+							}
+						}
+					}
+				},
+				"components": {
+					"schemas": {
+						"User": {"type": "object", "properties": {"age": ` + spec.age + `}}
+					},
+					"securitySchemes": {
+						"oauth": {"type": "oauth2", "flows": {"clientCredentials": {"tokenUrl": "https://auth.example.com/token", "scopes": {}}}}
+					}
+				}
+			}`
+
+			out, err := Convert([]byte(convertInput), spec.version)
+			if err != nil {
+				t.Fatalf("Convert failed: %v", err)
+			}
+
+			var got, want interface{}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("output is not valid JSON: %v", err)
+			}
+			if err := json.Unmarshal([]byte(expected), &want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Convert() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConvertRejectsUnknownVersion(t *testing.T) {
+	if _, err := Convert([]byte(convertInput), "4.0"); err == nil {
+		t.Error("expected an error for an unknown target version")
+	}
+}
@@ -0,0 +1,69 @@
+package genopenapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// envelopeSchema returns the schema of the named response envelope around the
+// schema of a payload, see descriptor.ResponseEnvelopes.
+func envelopeSchema(name string, payload openapiSchemaObject) openapiSchemaObject {
+	object := func(description string) openapiSchemaObject {
+		return openapiSchemaObject{
+			schemaCore:  schemaCore{Type: "object"},
+			Description: description,
+		}
+	}
+	code := openapiSchemaObject{
+		schemaCore:  schemaCore{Type: "integer", Format: "int32"},
+		Description: "The status code, 0 for success.",
+	}
+	message := openapiSchemaObject{
+		schemaCore:  schemaCore{Type: "string"},
+		Description: "The status message.",
+	}
+
+	var props openapiSchemaObjectProperties
+	switch name {
+	case "code-msg-data":
+		props = openapiSchemaObjectProperties{
+			{Key: "code", Value: code},
+			{Key: "msg", Value: message},
+			{Key: "data", Value: payload},
+		}
+	case "code-message-data":
+		props = openapiSchemaObjectProperties{
+			{Key: "code", Value: code},
+			{Key: "message", Value: message},
+			{Key: "data", Value: payload},
+		}
+	case "jsonapi":
+		props = openapiSchemaObjectProperties{
+			{Key: "data", Value: payload},
+			{Key: "meta", Value: object("Non-standard meta-information about the response.")},
+			{Key: "links", Value: object("Links related to the response.")},
+		}
+	}
+	schema := object("")
+	schema.Properties = &props
+	return schema
+}
+
+// wrapResponse wraps the schema of a success response in the envelope of the
+// registry. A response referring to a definition is wrapped in a definition
+// named after it, e.g. v1UserEnvelope for v1User, which is added to
+// envelopes. Other responses are wrapped inline.
+func wrapResponse(envelope string, schema openapiSchemaObject, envelopes openapiDefinitionsObject) openapiSchemaObject {
+	const definitionsPrefix = "#/definitions/"
+	if !strings.HasPrefix(schema.Ref, definitionsPrefix) {
+		return envelopeSchema(envelope, schema)
+	}
+
+	name := strings.TrimPrefix(schema.Ref, definitionsPrefix) + "Envelope"
+	if _, ok := envelopes[name]; !ok {
+		wrapper := envelopeSchema(envelope, openapiSchemaObject{schemaCore: schemaCore{Ref: schema.Ref}})
+		wrapper.Description = fmt.Sprintf("The %s envelope of %s.", envelope, strings.TrimPrefix(schema.Ref, definitionsPrefix))
+		envelopes[name] = wrapper
+	}
+	return openapiSchemaObject{schemaCore: schemaCore{Ref: definitionsPrefix + name}}
+}
@@ -0,0 +1,228 @@
+package genopenapi
+
+import (
+	"encoding/json"
+
+	"github.com/roverliang/grpc2openapi/openapi/descriptor"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// maxExampleDepth limits the nesting of generated examples, so that recursive
+// messages don't give endless examples.
+const maxExampleDepth = 8
+
+// bodyExample returns the example of the request body of a binding, or nil if
+// the request message has an explicit example. The fields bound to path
+// parameters are left out of the example of the whole request message.
+func bodyExample(meth *descriptor.Method, b *descriptor.Binding, reg *descriptor.Registry) (json.RawMessage, error) {
+	if len(b.Body.FieldPath) > 0 {
+		return fieldExample(b.Body.FieldPath[len(b.Body.FieldPath)-1].Target, reg), nil
+	}
+	if _, ok := wktSchemas[meth.RequestType.FQMN()]; ok {
+		return nil, nil
+	}
+	opts, err := getMessageOpenAPIOption(reg, meth.RequestType)
+	if err != nil {
+		return nil, err
+	}
+	if opts.GetExample() != "" {
+		return nil, nil
+	}
+
+	skip := map[*descriptor.Field]bool{}
+	for _, p := range b.PathParams {
+		if len(p.FieldPath) == 1 {
+			skip[p.Target] = true
+		}
+	}
+	return messageExample(meth.RequestType, reg, skip), nil
+}
+
+// messageExample builds an example of a message from its proto default values,
+// the default and example field options, and enum defaults. Fields in skip are
+// left out, e.g. the ones bound to path parameters.
+func messageExample(msg *descriptor.Message, reg *descriptor.Registry, skip map[*descriptor.Field]bool) json.RawMessage {
+	return messageExampleOf(msg, reg, skip, map[string]bool{})
+}
+
+func messageExampleOf(msg *descriptor.Message, reg *descriptor.Registry, skip map[*descriptor.Field]bool, seen map[string]bool) json.RawMessage {
+	if seen[msg.FQMN()] || len(seen) >= maxExampleDepth {
+		return json.RawMessage("{}")
+	}
+	seen[msg.FQMN()] = true
+	defer delete(seen, msg.FQMN())
+
+	props := openapiSchemaObjectProperties{}
+	for _, f := range msg.Fields {
+		if skip[f] {
+			continue
+		}
+		key := f.GetName()
+		if reg.GetUseJSONNamesForFields() {
+			key = f.GetJsonName()
+		}
+		props = append(props, keyVal{Key: key, Value: fieldExampleOf(f, reg, seen)})
+	}
+	example, err := json.Marshal(props)
+	if err != nil {
+		return json.RawMessage("{}")
+	}
+	return example
+}
+
+// fieldExample returns the example of a field. An explicit example, given by
+// the field options or an @example comment directive, is used as it is.
+func fieldExample(f *descriptor.Field, reg *descriptor.Registry) json.RawMessage {
+	return fieldExampleOf(f, reg, map[string]bool{})
+}
+
+func fieldExampleOf(f *descriptor.Field, reg *descriptor.Registry, seen map[string]bool) json.RawMessage {
+	schema := schemaOfField(f, reg, nil)
+	if f.Message != nil {
+		comments := fieldProtoComments(reg, f.Message, f)
+		if err := updateOpenAPIDataFromComments(reg, &schema, f, comments, false); err != nil {
+			schema.Example = nil
+		}
+	}
+	if len(schema.Example) > 0 {
+		if json.Valid(schema.Example) {
+			return schema.Example
+		}
+		quoted, _ := json.Marshal(string(schema.Example))
+		return quoted
+	}
+
+	fd := f.FieldDescriptorProto
+	mapEntry, isMap := mapEntryOf(f, reg)
+	if isMap {
+		fd = mapEntry.GetField()[1]
+	}
+
+	var value json.RawMessage
+	switch {
+	case schema.Default != "" && !isMap:
+		value = exampleFromDefault(schema.Default, scalarCore(schema))
+	case fd.GetDefaultValue() != "":
+		value = exampleFromDefault(fd.GetDefaultValue(), scalarCore(schema))
+	default:
+		value = zeroExample(fd, reg, seen)
+	}
+
+	switch {
+	case isMap:
+		key := `"key"`
+		if keyType, _, _ := primitiveSchema(mapEntry.GetField()[0].GetType()); keyType != "string" {
+			key = `"0"`
+		}
+		return json.RawMessage("{" + key + ":" + string(value) + "}")
+	case f.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REPEATED:
+		return json.RawMessage("[" + string(value) + "]")
+	default:
+		return value
+	}
+}
+
+// mapEntryOf returns the map entry message of a map field.
+func mapEntryOf(f *descriptor.Field, reg *descriptor.Registry) (*descriptor.Message, bool) {
+	if f.GetTypeName() == "" {
+		return nil, false
+	}
+	m, err := reg.LookupMsg("", f.GetTypeName())
+	if err != nil {
+		return nil, false
+	}
+	if opt := m.GetOptions(); opt != nil && opt.GetMapEntry() {
+		return m, true
+	}
+	return nil, false
+}
+
+// scalarCore returns the schema of the single values of a field.
+func scalarCore(schema openapiSchemaObject) schemaCore {
+	switch {
+	case schema.Items != nil:
+		return schemaCore(*schema.Items)
+	case schema.AdditionalProperties != nil:
+		return schema.AdditionalProperties.schemaCore
+	default:
+		return schema.schemaCore
+	}
+}
+
+// exampleFromDefault renders a default value, which is stored as a string, as
+// a JSON value of the schema's type.
+func exampleFromDefault(value string, core schemaCore) json.RawMessage {
+	if core.Type != "string" && core.Ref == "" && json.Valid([]byte(value)) {
+		return json.RawMessage(value)
+	}
+	var s string
+	if err := json.Unmarshal([]byte(value), &s); err == nil {
+		// already a JSON string
+		return json.RawMessage(value)
+	}
+	quoted, _ := json.Marshal(value)
+	return quoted
+}
+
+// zeroExample returns the proto3 default value of a single field value in its
+// JSON form, the enum default for enums and a nested example for messages.
+func zeroExample(fd *descriptorpb.FieldDescriptorProto, reg *descriptor.Registry, seen map[string]bool) json.RawMessage {
+	switch fd.GetType() {
+	case descriptorpb.FieldDescriptorProto_TYPE_ENUM:
+		enum, err := reg.LookupEnum("", fd.GetTypeName())
+		if err != nil || len(enum.GetValue()) == 0 {
+			return json.RawMessage("null")
+		}
+		if reg.GetEnumsAsInts() {
+			return json.RawMessage("0")
+		}
+		name := getEnumDefault(enum)
+		if name == "" {
+			name = enum.GetValue()[0].GetName()
+		}
+		quoted, _ := json.Marshal(name)
+		return quoted
+	case descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, descriptorpb.FieldDescriptorProto_TYPE_GROUP:
+		switch fd.GetTypeName() {
+		case ".google.protobuf.Timestamp":
+			return json.RawMessage(`"1970-01-01T00:00:00Z"`)
+		case ".google.protobuf.Duration":
+			return json.RawMessage(`"0s"`)
+		}
+		if core, ok := wktSchemas[fd.GetTypeName()]; ok {
+			return zeroOfType(core.Type, core.Format)
+		}
+		msg, err := reg.LookupMsg("", fd.GetTypeName())
+		if err != nil {
+			return json.RawMessage("{}")
+		}
+		return messageExampleOf(msg, reg, nil, seen)
+	default:
+		ftype, format, ok := primitiveSchema(fd.GetType())
+		if !ok {
+			return json.RawMessage("null")
+		}
+		return zeroOfType(ftype, format)
+	}
+}
+
+func zeroOfType(ftype, format string) json.RawMessage {
+	switch ftype {
+	case "string":
+		switch format {
+		case "int64", "uint64":
+			return json.RawMessage(`"0"`)
+		}
+		return json.RawMessage(`""`)
+	case "integer", "number":
+		return json.RawMessage("0")
+	case "boolean":
+		return json.RawMessage("false")
+	case "array":
+		return json.RawMessage("[]")
+	case "object":
+		return json.RawMessage("{}")
+	default:
+		return json.RawMessage("null")
+	}
+}
@@ -0,0 +1,23 @@
+package genopenapi
+
+import "testing"
+
+func TestExampleFromDefault(t *testing.T) {
+	for _, spec := range []struct {
+		value string
+		core  schemaCore
+		want  string
+	}{
+		{value: "42", core: schemaCore{Type: "integer"}, want: `42`},
+		{value: "true", core: schemaCore{Type: "boolean"}, want: `true`},
+		{value: "42", core: schemaCore{Type: "string", Format: "int64"}, want: `"42"`},
+		{value: "hello", core: schemaCore{Type: "string"}, want: `"hello"`},
+		{value: `"hello"`, core: schemaCore{Type: "string"}, want: `"hello"`},
+		{value: "ACTIVE", core: schemaCore{Ref: "#/definitions/State"}, want: `"ACTIVE"`},
+		{value: "not json", core: schemaCore{Type: "integer"}, want: `"not json"`},
+	} {
+		if got := string(exampleFromDefault(spec.value, spec.core)); got != spec.want {
+			t.Errorf("exampleFromDefault(%q, %+v) = %s, want %s", spec.value, spec.core, got, spec.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/golang/glog"
@@ -100,6 +101,36 @@ func (so openapiResponseObject) MarshalJSON() ([]byte, error) {
 	return extensionMarshalJSON(alias(so), so.extensions)
 }
 
+func (so openapiSchemaObject) MarshalJSON() ([]byte, error) {
+	type alias openapiSchemaObject
+	return extensionMarshalJSON(alias(so), so.extensions)
+}
+
+func (so openapiV3Document) MarshalJSON() ([]byte, error) {
+	type alias openapiV3Document
+	return extensionMarshalJSON(alias(so), so.extensions)
+}
+
+func (so openapiV3SecuritySchemeObject) MarshalJSON() ([]byte, error) {
+	type alias openapiV3SecuritySchemeObject
+	return extensionMarshalJSON(alias(so), so.extensions)
+}
+
+func (so openapiV3OperationObject) MarshalJSON() ([]byte, error) {
+	type alias openapiV3OperationObject
+	return extensionMarshalJSON(alias(so), so.extensions)
+}
+
+func (so openapiV3ResponseObject) MarshalJSON() ([]byte, error) {
+	type alias openapiV3ResponseObject
+	return extensionMarshalJSON(alias(so), so.extensions)
+}
+
+func (so openapiV3SchemaObject) MarshalJSON() ([]byte, error) {
+	type alias openapiV3SchemaObject
+	return extensionMarshalJSON(alias(so), so.extensions)
+}
+
 func extensionMarshalJSON(so interface{}, extensions []extension) ([]byte, error) {
 	// To append arbitrary keys to the struct we'll render into json,
 	// we're creating another struct that embeds the original one, and
@@ -139,10 +170,8 @@ func extensionMarshalJSON(so interface{}, extensions []extension) ([]byte, error
 
 // encodeOpenAPI converts OpenAPI file obj to pluginpb.CodeGeneratorResponse_File
 func encodeOpenAPI(file *wrapper) (*descriptor.ResponseFile, error) {
-	var formatted bytes.Buffer
-	enc := json.NewEncoder(&formatted)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(*file.swagger); err != nil {
+	formatted, err := formatOpenAPI(file.swagger)
+	if err != nil {
 		return nil, err
 	}
 	name := file.fileName
@@ -152,11 +181,94 @@ func encodeOpenAPI(file *wrapper) (*descriptor.ResponseFile, error) {
 	return &descriptor.ResponseFile{
 		CodeGeneratorResponse_File: &pluginpb.CodeGeneratorResponse_File{
 			Name:    proto.String(output),
-			Content: proto.String(formatted.String()),
+			Content: proto.String(string(formatted)),
 		},
+		Extensions: listExtensions(file.swagger),
 	}, nil
 }
 
+// listExtensions returns the vendor extensions of a document, sorted by location.
+func listExtensions(swagger *openapiSwaggerObject) []descriptor.ExtensionUse {
+	var uses []descriptor.ExtensionUse
+	add := func(location string, exts []extension) {
+		for _, ext := range exts {
+			source := ext.source
+			if source == "" {
+				source = "unknown"
+			}
+			uses = append(uses, descriptor.ExtensionUse{Location: location, Key: ext.key, Source: source})
+		}
+	}
+	var addSchema func(location string, schema *openapiSchemaObject)
+	addSchema = func(location string, schema *openapiSchemaObject) {
+		add(location, schema.extensions)
+		if schema.AdditionalProperties != nil {
+			addSchema(location+"/additionalProperties", schema.AdditionalProperties)
+		}
+		if schema.Properties != nil {
+			for _, prop := range *schema.Properties {
+				if value, ok := prop.Value.(openapiSchemaObject); ok {
+					addSchema(location+"/properties/"+jsonPointerEscape(prop.Key), &value)
+				}
+			}
+		}
+	}
+
+	add("", swagger.extensions)
+	add("/info", swagger.Info.extensions)
+	for name, scheme := range swagger.SecurityDefinitions {
+		add("/securityDefinitions/"+jsonPointerEscape(name), scheme.extensions)
+	}
+	for path, item := range swagger.Paths {
+		for method, op := range map[string]*openapiOperationObject{
+			"get": item.Get, "delete": item.Delete, "post": item.Post, "put": item.Put, "patch": item.Patch,
+		} {
+			if op == nil {
+				continue
+			}
+			location := "/paths/" + jsonPointerEscape(path) + "/" + method
+			add(location, op.extensions)
+			for code, resp := range op.Responses {
+				add(location+"/responses/"+code, resp.extensions)
+				addSchema(location+"/responses/"+code+"/schema", &resp.Schema)
+			}
+			for i, param := range op.Parameters {
+				if param.Schema != nil {
+					addSchema(fmt.Sprintf("%s/parameters/%d/schema", location, i), param.Schema)
+				}
+			}
+		}
+	}
+	for name, def := range swagger.Definitions {
+		addSchema("/definitions/"+jsonPointerEscape(name), &def)
+	}
+
+	sort.Slice(uses, func(i, j int) bool {
+		if uses[i].Location != uses[j].Location {
+			return uses[i].Location < uses[j].Location
+		}
+		return uses[i].Key < uses[j].Key
+	})
+	return uses
+}
+
+// jsonPointerEscape escapes a reference token of a JSON pointer.
+func jsonPointerEscape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// formatOpenAPI renders a document, either a *openapiSwaggerObject or a
+// *openapiV3Document, as indented JSON the way generated files are written.
+func formatOpenAPI(doc interface{}) ([]byte, error) {
+	var formatted bytes.Buffer
+	enc := json.NewEncoder(&formatted)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return formatted.Bytes(), nil
+}
+
 func (g *generator) Generate(targets []*descriptor.File) ([]*descriptor.ResponseFile, error) {
 	var files []*descriptor.ResponseFile
 	if g.reg.IsAllowMerge() {
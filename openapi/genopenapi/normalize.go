@@ -0,0 +1,291 @@
+package genopenapi
+
+import (
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+)
+
+// Normalize canonicalizes an OpenAPI v2 document, so that hand-written and
+// generated documents can be compared. The document is read into the model
+// used by the generator and rendered the same way generated files are:
+//
+//   - object keys, properties, tags and required fields are sorted
+//   - schemes, media types, tags, parameters and enum values are deduplicated
+//   - references are rewritten to the "#/definitions/Name" form, and
+//     definitions which are only a reference to another one are resolved
+//   - types, formats, schemes and media types are lower cased, header names
+//     are canonicalized
+//
+// Fields which the model can't represent are dropped.
+func Normalize(in []byte) ([]byte, error) {
+	swagger, err := decodeOpenAPI(in)
+	if err != nil {
+		return nil, err
+	}
+	normalizeSwagger(swagger)
+	return formatOpenAPI(swagger)
+}
+
+func normalizeSwagger(s *openapiSwaggerObject) {
+	s.Schemes = normalizeStrings(s.Schemes, true)
+	s.Consumes = normalizeStrings(s.Consumes, true)
+	s.Produces = normalizeStrings(s.Produces, true)
+
+	tags := map[string]openapiTagObject{}
+	for _, tag := range s.Tags {
+		if _, ok := tags[tag.Name]; !ok {
+			tags[tag.Name] = tag
+		}
+	}
+	s.Tags = s.Tags[:0]
+	for _, tag := range tags {
+		s.Tags = append(s.Tags, tag)
+	}
+	sort.Slice(s.Tags, func(i, j int) bool { return s.Tags[i].Name < s.Tags[j].Name })
+
+	for _, req := range s.Security {
+		for _, scopes := range req {
+			sort.Strings(scopes)
+		}
+	}
+
+	aliases := definitionAliases(s.Definitions)
+	for name := range aliases {
+		glog.V(1).Infof("resolving definition %s which only references %s", name, aliases[name])
+		delete(s.Definitions, name)
+	}
+
+	for name, def := range s.Definitions {
+		normalizeSchema(&def, aliases)
+		s.Definitions[name] = def
+	}
+	for name, param := range s.Parameters {
+		normalizeParameter(&param, aliases)
+		s.Parameters[name] = param
+	}
+	for path, item := range s.Paths {
+		for _, op := range []*openapiOperationObject{item.Get, item.Delete, item.Post, item.Put, item.Patch} {
+			if op != nil {
+				normalizeOperation(op, aliases)
+			}
+		}
+		s.Paths[path] = item
+	}
+}
+
+func normalizeOperation(op *openapiOperationObject, aliases map[string]string) {
+	op.Tags = normalizeStrings(op.Tags, false)
+	op.Consumes = normalizeStrings(op.Consumes, true)
+	op.Produces = normalizeStrings(op.Produces, true)
+
+	seen := map[string]bool{}
+	params := op.Parameters[:0]
+	for _, param := range op.Parameters {
+		normalizeParameter(&param, aliases)
+		key := param.In + "/" + param.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		params = append(params, param)
+	}
+	sort.SliceStable(params, func(i, j int) bool {
+		if params[i].In != params[j].In {
+			return parameterLocationOrder(params[i].In) < parameterLocationOrder(params[j].In)
+		}
+		return params[i].Name < params[j].Name
+	})
+	op.Parameters = params
+
+	if op.Security != nil {
+		for _, req := range *op.Security {
+			for _, scopes := range req {
+				sort.Strings(scopes)
+			}
+		}
+	}
+
+	for code, resp := range op.Responses {
+		normalizeSchema(&resp.Schema, aliases)
+		if len(resp.Headers) > 0 {
+			headers := openapiHeadersObject{}
+			for name, header := range resp.Headers {
+				header.Type = strings.ToLower(header.Type)
+				header.Format = strings.ToLower(header.Format)
+				headers[textproto.CanonicalMIMEHeaderKey(name)] = header
+			}
+			resp.Headers = headers
+		}
+		op.Responses[code] = resp
+	}
+}
+
+// parameterLocationOrder sorts parameters the way the generator emits them.
+func parameterLocationOrder(in string) int {
+	switch in {
+	case "path":
+		return 0
+	case "body":
+		return 1
+	case "query":
+		return 2
+	case "header":
+		return 3
+	default:
+		return 4
+	}
+}
+
+func normalizeParameter(param *openapiParameterObject, aliases map[string]string) {
+	param.In = strings.ToLower(param.In)
+	param.Type = strings.ToLower(param.Type)
+	param.Format = strings.ToLower(param.Format)
+	param.Enum = dedupeStrings(param.Enum)
+	if param.In == "header" {
+		param.Name = textproto.CanonicalMIMEHeaderKey(param.Name)
+	}
+	if param.Ref != "" {
+		param.Ref = canonicalRef(param.Ref, "parameters", nil)
+	}
+	if param.Items != nil {
+		normalizeItems(param.Items, aliases)
+	}
+	if param.Schema != nil {
+		normalizeSchema(param.Schema, aliases)
+	}
+}
+
+func normalizeSchema(s *openapiSchemaObject, aliases map[string]string) {
+	s.Type = strings.ToLower(s.Type)
+	s.Format = strings.ToLower(s.Format)
+	s.Enum = dedupeStrings(s.Enum)
+	if s.Ref != "" {
+		s.Ref = canonicalRef(s.Ref, "definitions", aliases)
+	}
+	if s.Items != nil {
+		normalizeItems(s.Items, aliases)
+	}
+	if s.AdditionalProperties != nil {
+		normalizeSchema(s.AdditionalProperties, aliases)
+	}
+	if len(s.Required) > 0 {
+		s.Required = normalizeStrings(s.Required, false)
+	}
+	if s.Properties != nil {
+		props := *s.Properties
+		for i, prop := range props {
+			if value, ok := prop.Value.(openapiSchemaObject); ok {
+				normalizeSchema(&value, aliases)
+				props[i].Value = value
+			}
+		}
+		sort.SliceStable(props, func(i, j int) bool { return props[i].Key < props[j].Key })
+	}
+}
+
+func normalizeItems(items *openapiItemsObject, aliases map[string]string) {
+	items.Type = strings.ToLower(items.Type)
+	items.Format = strings.ToLower(items.Format)
+	items.Enum = dedupeStrings(items.Enum)
+	if items.Ref != "" {
+		items.Ref = canonicalRef(items.Ref, "definitions", aliases)
+	}
+	if items.Items != nil {
+		normalizeItems(items.Items, aliases)
+	}
+}
+
+// definitionAliases returns the definitions which consist of nothing but a
+// reference to another definition, mapped to the definition they finally
+// resolve to.
+func definitionAliases(d openapiDefinitionsObject) map[string]string {
+	direct := map[string]string{}
+	for name, def := range d {
+		if def.Ref == "" || !reflect.DeepEqual(def, openapiSchemaObject{schemaCore: schemaCore{Ref: def.Ref}}) {
+			continue
+		}
+		target := strings.TrimPrefix(canonicalRef(def.Ref, "definitions", nil), "#/definitions/")
+		if target != name {
+			direct[name] = target
+		}
+	}
+
+	aliases := map[string]string{}
+	for name := range direct {
+		target, seen := name, map[string]bool{}
+		for {
+			next, ok := direct[target]
+			if !ok || seen[next] {
+				break
+			}
+			seen[target] = true
+			target = next
+		}
+		if _, isAlias := direct[target]; isAlias {
+			// a cycle of references, keep the definitions as they are
+			continue
+		}
+		aliases[name] = target
+	}
+	return aliases
+}
+
+// canonicalRef rewrites a local reference to the "#/<section>/Name" form and
+// resolves definition aliases. Remote references are left as they are.
+func canonicalRef(ref, section string, aliases map[string]string) string {
+	name := ref
+	switch {
+	case strings.HasPrefix(ref, "#/"+section+"/"):
+		name = strings.TrimPrefix(ref, "#/"+section+"/")
+	case strings.HasPrefix(ref, "$/"+section+"/"):
+		name = strings.TrimPrefix(ref, "$/"+section+"/")
+	case strings.HasPrefix(ref, section+"/"):
+		name = strings.TrimPrefix(ref, section+"/")
+	case strings.ContainsAny(ref, "#/"):
+		return ref
+	}
+	if target, ok := aliases[name]; ok {
+		name = target
+	}
+	return "#/" + section + "/" + name
+}
+
+// normalizeStrings deduplicates and sorts a list of strings, optionally lower
+// casing them first.
+func normalizeStrings(in []string, lower bool) []string {
+	if len(in) == 0 {
+		return in
+	}
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if lower {
+			s = strings.ToLower(s)
+		}
+		out = append(out, s)
+	}
+	out = dedupeStrings(out)
+	sort.Strings(out)
+	return out
+}
+
+// dedupeStrings removes duplicates while keeping the order, which matters for
+// enum values.
+func dedupeStrings(in []string) []string {
+	if len(in) == 0 {
+		return in
+	}
+	seen := make(map[string]bool, len(in))
+	out := in[:0]
+	for _, s := range in {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	return out
+}
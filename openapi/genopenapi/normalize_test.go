@@ -0,0 +1,118 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalize(t *testing.T) {
+	in := `{
+		"swagger": "2.0",
+		"info": {"title": "users", "version": "1", "x-owner": "team"},
+		"schemes": ["HTTPS", "http", "https"],
+		"consumes": ["Application/JSON"],
+		"produces": ["application/json"],
+		"paths": {
+			"/v1/users": {
+				"get": {
+					"operationId": "List",
+					"tags": ["Users", "Users"],
+					"parameters": [
+						{"name": "page", "in": "query", "type": "integer", "format": "Int32"},
+						{"name": "id", "in": "path", "required": true, "type": "string"},
+						{"name": "page", "in": "query", "type": "integer"}
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"schema": {"$ref": "UserList"},
+							"headers": {"x-request-id": {"type": "string"}}
+						}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"UserList": {"$ref": "#/definitions/ListUsersResponse"},
+			"ListUsersResponse": {
+				"type": "object",
+				"required": ["users", "next", "users"],
+				"properties": {
+					"users": {"type": "array", "items": {"$ref": "$/definitions/User"}},
+					"next": {"type": "string", "default": 5}
+				}
+			},
+			"User": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}
+	}`
+	expected := `{
+		"swagger": "2.0",
+		"parameters": null,
+		"info": {"title": "users", "version": "1", "x-owner": "team"},
+		"schemes": ["http", "https"],
+		"consumes": ["application/json"],
+		"produces": ["application/json"],
+		"paths": {
+			"/v1/users": {
+				"get": {
+					"operationId": "List",
+					"tags": ["Users"],
+					"parameters": [
+						{"name": "id", "in": "path", "required": true, "type": "string"},
+						{"name": "page", "in": "query", "type": "integer", "format": "int32"}
+					],
+					"responses": {
+						"200": {
+							"description": "ok",
+							"schema": {"$ref": "#/definitions/ListUsersResponse"},
+							"headers": {"X-Request-Id": {"type": "string"}}
+						}
+					}
+				}
+			}
+		},
+		"definitions": {
+			"ListUsersResponse": {
+				"type": "object",
+				"required": ["next", "users"],
+				"properties": {
+					"next": {"type": "string", "default": "5"},
+					"users": {"type": "array", "items": {"$ref": "#/definitions/User"}}
+				}
+			},
+			"User": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}
+	}`
+
+	out, err := Normalize([]byte(in))
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	var got, want interface{}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Normalize() mismatch (-want +got):\n%s", diff)
+	}
+
+	again, err := Normalize(out)
+	if err != nil {
+		t.Fatalf("Normalize of normalized output failed: %v", err)
+	}
+	if string(again) != string(out) {
+		t.Errorf("Normalize is not idempotent:\n%s\n%s", out, again)
+	}
+}
+
+func TestNormalizeRejectsOpenAPI3(t *testing.T) {
+	if _, err := Normalize([]byte(`{"openapi": "3.0.0"}`)); err == nil {
+		t.Error("expected an error for an OpenAPI 3 document")
+	}
+}
@@ -423,6 +423,10 @@ func renderMessagesAsDefinition(messages messageMap, d openapiDefinitionsObject,
 				}
 			}
 
+			if title, ok := reg.GetTitle(f.FQFN()); ok {
+				fieldValue.Title = title
+			}
+
 			kv := keyVal{Value: fieldValue}
 			if reg.GetUseJSONNamesForFields() {
 				kv.Key = f.GetJsonName()
@@ -434,10 +438,55 @@ func renderMessagesAsDefinition(messages messageMap, d openapiDefinitionsObject,
 			}
 			*schema.Properties = append(*schema.Properties, kv)
 		}
+		renderConstraints(&schema, msg, reg)
+		if title, ok := reg.GetTitle(msg.FQMN()); ok {
+			schema.Title = title
+		}
 		d[swgName] = schema
 	}
 }
 
+// renderConstraints renders the cross-field constraints of a message as the
+// x-constraints extension, and appends them to the description since OpenAPI
+// 2.0 can't express them and many tools ignore extensions.
+func renderConstraints(schema *openapiSchemaObject, msg *descriptor.Message, reg *descriptor.Registry) {
+	constraints := reg.GetConstraints(msg.FQMN())
+	if len(constraints) == 0 {
+		return
+	}
+
+	type constraint struct {
+		Type        string   `json:"type"`
+		Fields      []string `json:"fields"`
+		Description string   `json:"description"`
+	}
+	rendered := make([]constraint, 0, len(constraints))
+	lines := make([]string, 0, len(constraints))
+	for _, c := range constraints {
+		fields := make([]string, 0, len(c.Fields))
+		for _, name := range c.Fields {
+			for _, f := range msg.Fields {
+				if f.GetName() != name {
+					continue
+				}
+				if reg.GetUseJSONNamesForFields() {
+					name = f.GetJsonName()
+				}
+				break
+			}
+			fields = append(fields, name)
+		}
+		rendered = append(rendered, constraint{Type: c.Type, Fields: fields, Description: c.Description})
+		lines = append(lines, "- "+c.Description)
+	}
+	addExtension(schema, "x-constraints", rendered, extensionSourceConfig)
+
+	if schema.Description != "" {
+		schema.Description += "\n\n"
+	}
+	schema.Description += "Constraints:\n" + strings.Join(lines, "\n")
+}
+
 // schemaOfField returns a OpenAPI Schema Object for a protobuf field.
 func schemaOfField(f *descriptor.Field, reg *descriptor.Registry, refs refMap) openapiSchemaObject {
 	const (
@@ -609,6 +658,9 @@ func renderEnumerationsAsDefinition(enums enumMap, d openapiDefinitionsObject, r
 		if err := updateOpenAPIDataFromComments(reg, &enumSchemaObject, enum, enumComments, false); err != nil {
 			panic(err)
 		}
+		if title, ok := reg.GetTitle(enum.FQEN()); ok {
+			enumSchemaObject.Title = title
+		}
 
 		d[swgName] = enumSchemaObject
 	}
@@ -624,6 +676,9 @@ func fullyQualifiedNameToOpenAPIName(fqn string, reg *descriptor.Registry) (stri
 		return ret, ok
 	}
 	mapping := resolveFullyQualifiedNameToOpenAPINames(append(reg.GetAllFQMNs(), reg.GetAllFQENs()...), reg.GetUseFQNForOpenAPIName())
+	if manifest := reg.GetNamingManifest(); manifest != nil {
+		manifest.PinDefinitionNames(mapping)
+	}
 	registriesSeen[reg] = mapping
 	ret, ok := mapping[fqn]
 	return ret, ok
@@ -809,7 +864,7 @@ func renderServiceTags(services []*descriptor.Service) []openapiTagObject {
 	return tags
 }
 
-func renderServices(services []*descriptor.Service, paths openapiPathsObject, reg *descriptor.Registry, requestResponseRefs, customRefs refMap, msgs []*descriptor.Message) error {
+func renderServices(services []*descriptor.Service, paths openapiPathsObject, reg *descriptor.Registry, requestResponseRefs, customRefs refMap, msgs []*descriptor.Message, envelopes openapiDefinitionsObject) error {
 	// Correctness of svcIdx and methIdx depends on 'services' containing the services in the same order as the 'file.Service' array.
 	svcBaseIdx := 0
 	var lastFile *descriptor.File = nil
@@ -934,16 +989,19 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 					} else {
 						lastField := b.Body.FieldPath[len(b.Body.FieldPath)-1]
 						schema = schemaOfField(lastField.Target, reg, customRefs)
-						if schema.Description != "" {
-							desc = schema.Description
-						} else {
-							desc = fieldProtoComments(reg, lastField.Target.Message, lastField.Target)
-						}
+						desc = fieldPathDescription(reg, b.Body.FieldPath, schema.Description)
 					}
 
 					if meth.GetClientStreaming() {
 						desc += " (streaming inputs)"
 					}
+					if reg.IsGenerateBodyExamples() && len(schema.Example) == 0 {
+						example, err := bodyExample(meth, b, reg)
+						if err != nil {
+							return err
+						}
+						schema.Example = example
+					}
 					parameters = append(parameters, openapiParameterObject{
 						Name:        "body",
 						Description: desc,
@@ -1002,11 +1060,7 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 					// This is resolving the value of response_body in the google.api.HttpRule
 					lastField := b.ResponseBody.FieldPath[len(b.ResponseBody.FieldPath)-1]
 					responseSchema = schemaOfField(lastField.Target, reg, customRefs)
-					if responseSchema.Description != "" {
-						desc = responseSchema.Description
-					} else {
-						desc = fieldProtoComments(reg, lastField.Target.Message, lastField.Target)
-					}
+					desc = fieldPathDescription(reg, b.ResponseBody.FieldPath, responseSchema.Description)
 				}
 				if meth.GetServerStreaming() {
 					desc += "(streaming responses)"
@@ -1038,6 +1092,19 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 					responseSchema.Ref = ""
 				}
 
+				if override, ok := reg.GetResponseOverride(meth); ok {
+					schema, err := overrideResponseSchema(override, responseSchema)
+					if err != nil {
+						return fmt.Errorf("failed to override the response of %s: %v", meth.FQMN(), err)
+					}
+					responseSchema = schema
+					if override.Description != "" {
+						desc = override.Description
+					}
+				} else if envelope := reg.GetResponseEnvelope(); envelope != "" && !meth.GetServerStreaming() {
+					responseSchema = wrapResponse(envelope, responseSchema, envelopes)
+				}
+
 				tag := svc.GetName()
 				if pkg := svc.File.GetPackage(); pkg != "" && reg.IsIncludePackageInTags() {
 					tag = pkg + "." + tag
@@ -1076,6 +1143,9 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 					// OperationID must be unique in an OpenAPI v2 definition.
 					operationObject.OperationID += strconv.Itoa(bIdx + 1)
 				}
+				if manifest := reg.GetNamingManifest(); manifest != nil {
+					operationObject.OperationID = manifest.PinOperationID(descriptor.OperationKey(meth, bIdx), operationObject.OperationID)
+				}
 
 				// Fill reference map with referenced request messages
 				for _, param := range operationObject.Parameters {
@@ -1096,7 +1166,9 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 					}
 					operationObject.ExternalDocs = protoExternalDocumentationToOpenAPIExternalDocumentation(opts.ExternalDocs, reg, meth)
 					// TODO(ivucica): this would be better supported by looking whether the method is deprecated in the proto file
-					operationObject.Deprecated = opts.Deprecated
+					if opts.Deprecated {
+						operationObject.Deprecated = true
+					}
 
 					if opts.Summary != "" {
 						operationObject.Summary = opts.Summary
@@ -1170,7 +1242,7 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 						if err != nil {
 							return err
 						}
-						operationObject.extensions = exts
+						operationObject.extensions = append(operationObject.extensions, exts...)
 					}
 
 					if len(opts.Produces) > 0 {
@@ -1181,6 +1253,10 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 					// TODO(ivucica): add remaining fields of operation object
 				}
 
+				if limit, ok := reg.GetRateLimit(meth); ok {
+					addExtension(operationObject, "x-ratelimit", limit, extensionSourceConfig)
+				}
+
 				switch b.HTTPMethod {
 				case "DELETE":
 					pathItemObject.Delete = operationObject
@@ -1198,11 +1274,21 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 
 				path = templateToOpenAPIPath(b.PathTmpl.Template, reg, meth.RequestType.Fields, msgs)
 				if reg.GetNamespace() != ""{
+					// unbound methods already carry the package path prefix
+					if prefix := reg.PackagePathPrefix(svc.File.GetPackage()); !strings.HasPrefix(path, prefix+"/") {
+						path = prefix + path
+					}
 					path = fmt.Sprintf("/%s%s", reg.GetNamespace(), path)
 				}
 
 				paths[path] = pathItemObject
 			}
+
+			if reg.IsEmitGRPCWebPaths() {
+				if err := renderGRPCWebPath(svc, meth, paths, reg, requestResponseRefs); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -1210,6 +1296,90 @@ func renderServices(services []*descriptor.Service, paths openapiPathsObject, re
 	return nil
 }
 
+// grpcWebContentTypes are the media types of the raw gRPC-Web endpoints.
+var grpcWebContentTypes = []string{"application/grpc-web-text"}
+
+// renderGRPCWebPath documents the raw gRPC-Web endpoint of a method,
+// "/pkg.Service/Method". Its body is a base64 encoded, length prefixed protobuf
+// message, so the message types are given by the x-proto-schema extension.
+// This is experimental.
+func renderGRPCWebPath(svc *descriptor.Service, meth *descriptor.Method, paths openapiPathsObject, reg *descriptor.Registry, requestResponseRefs refMap) error {
+	if meth.GetClientStreaming() {
+		glog.V(1).Infof("%s: client streaming is not supported by gRPC-Web", meth.FQMN())
+		return nil
+	}
+
+	path := fmt.Sprintf("/%s/%s", strings.TrimPrefix(svc.FQSN(), "."), meth.GetName())
+	if item, ok := paths[path]; ok && item.Post != nil {
+		glog.Warningf("%s: the gRPC-Web path %s is already bound to an HTTP rule, skipping", meth.FQMN(), path)
+		return nil
+	}
+
+	type protoMessage struct {
+		Ref  string `json:"$ref"`
+		Type string `json:"type"`
+	}
+	var protoSchema struct {
+		Request  protoMessage `json:"request"`
+		Response protoMessage `json:"response"`
+	}
+	for _, m := range []struct {
+		msg    *descriptor.Message
+		target *protoMessage
+	}{
+		{meth.RequestType, &protoSchema.Request},
+		{meth.ResponseType, &protoSchema.Response},
+	} {
+		name, ok := fullyQualifiedNameToOpenAPIName(m.msg.FQMN(), reg)
+		if !ok {
+			return fmt.Errorf("can't resolve OpenAPI name from '%v'", m.msg.FQMN())
+		}
+		m.target.Ref = fmt.Sprintf("#/definitions/%s", name)
+		m.target.Type = strings.TrimPrefix(m.msg.FQMN(), ".")
+	}
+	// the response type is always rendered, the request type only if referenced
+	requestResponseRefs[protoSchema.Request.Ref] = struct{}{}
+	ext, err := json.Marshal(protoSchema)
+	if err != nil {
+		return err
+	}
+
+	tag := svc.GetName()
+	if pkg := svc.File.GetPackage(); pkg != "" && reg.IsIncludePackageInTags() {
+		tag = pkg + "." + tag
+	}
+	desc := fmt.Sprintf("base64 encoded gRPC-Web frames of %s messages, followed by a trailers frame", protoSchema.Response.Type)
+	if meth.GetServerStreaming() {
+		desc += " (streaming responses)"
+	}
+	item := paths[path]
+	item.Post = &openapiOperationObject{
+		Summary:     fmt.Sprintf("gRPC-Web endpoint of %s", meth.GetName()),
+		OperationID: fmt.Sprintf("%s_%s_GrpcWeb", svc.GetName(), meth.GetName()),
+		Tags:        []string{tag},
+		Consumes:    grpcWebContentTypes,
+		Produces:    grpcWebContentTypes,
+		Parameters: openapiParametersObject{
+			{
+				Name:        "body",
+				In:          "body",
+				Required:    true,
+				Description: fmt.Sprintf("base64 encoded gRPC-Web frame of a %s message", protoSchema.Request.Type),
+				Schema:      &openapiSchemaObject{schemaCore: schemaCore{Type: "string", Format: "byte"}},
+			},
+		},
+		Responses: openapiResponsesObject{
+			"200": openapiResponseObject{
+				Description: desc,
+				Schema:      openapiSchemaObject{schemaCore: schemaCore{Type: "string", Format: "byte"}},
+			},
+		},
+		extensions: []extension{{key: "x-proto-schema", value: ext, source: extensionSourceGenerated}},
+	}
+	paths[path] = item
+	return nil
+}
+
 // This function is called with a param which contains the entire definition of a method.
 func applyTemplate(p param) (*openapiSwaggerObject, error) {
 	// Create the basic template object. This is the object that everything is
@@ -1231,7 +1401,8 @@ func applyTemplate(p param) (*openapiSwaggerObject, error) {
 	// and create entries for all of them.
 	// Also adds custom user specified references to second map.
 	requestResponseRefs, customRefs := refMap{}, refMap{}
-	if err := renderServices(p.Services, s.Paths, p.reg, requestResponseRefs, customRefs, p.Messages); err != nil {
+	envelopes := openapiDefinitionsObject{}
+	if err := renderServices(p.Services, s.Paths, p.reg, requestResponseRefs, customRefs, p.Messages, envelopes); err != nil {
 		panic(err)
 	}
 	s.Tags = append(s.Tags, renderServiceTags(p.Services)...)
@@ -1256,6 +1427,9 @@ func applyTemplate(p param) (*openapiSwaggerObject, error) {
 	findServicesMessagesAndEnumerations(p.Services, p.reg, messages, streamingMessages, enums, requestResponseRefs)
 	renderMessagesAsDefinition(messages, s.Definitions, p.reg, customRefs)
 	renderEnumerationsAsDefinition(enums, s.Definitions, p.reg)
+	for name, envelope := range envelopes {
+		s.Definitions[name] = envelope
+	}
 
 	// File itself might have some comments and metadata.
 	packageProtoPath := protoPathIndex(reflect.TypeOf((*descriptorpb.FileDescriptorProto)(nil)), "Package")
@@ -1472,7 +1646,7 @@ func applyTemplate(p param) (*openapiSwaggerObject, error) {
 			if err != nil {
 				return nil, err
 			}
-			s.extensions = exts
+			s.extensions = append(s.extensions, exts...)
 		}
 
 		// Additional fields on the OpenAPI v2 spec's "OpenAPI" object
@@ -1496,7 +1670,7 @@ func processExtensions(inputExts map[string]*structpb.Value) ([]extension, error
 		if err != nil {
 			return nil, err
 		}
-		exts = append(exts, extension{key: k, value: json.RawMessage(ext)})
+		exts = append(exts, extension{key: k, value: json.RawMessage(ext), source: extensionSourceOption})
 	}
 	sort.Slice(exts, func(i, j int) bool { return exts[i].key < exts[j].key })
 	return exts, nil
@@ -1698,6 +1872,19 @@ func updateOpenAPIDataFromComments(reg *descriptor.Registry, swaggerObject inter
 		comment = goTemplateComments(comment, data, reg)
 	}
 
+	// Lightweight directives such as "@since v1.4" are not part of the
+	// documentation text, they are applied to the object directly.
+	comment, directives := parseCommentDirectives(comment)
+	for _, d := range directives {
+		if d.name == "deprecated" && d.value != "" {
+			comment = strings.TrimSpace(comment + "\n\nDeprecated: " + d.value)
+		}
+	}
+	applyCommentDirectives(swaggerObject, directives)
+	if len(comment) == 0 {
+		return nil
+	}
+
 	// Figure out what to apply changes to.
 	swaggerObjectValue := reflect.ValueOf(swaggerObject)
 	infoObjectValue := swaggerObjectValue.Elem().FieldByName("Info")
@@ -1761,6 +1948,155 @@ func updateOpenAPIDataFromComments(reg *descriptor.Registry, swaggerObject inter
 	return fmt.Errorf("no description nor summary property")
 }
 
+// commentDirective is a "@name value" line inside a proto comment. The value
+// continues on the following lines up to the next blank line or directive.
+type commentDirective struct {
+	name  string
+	value string
+}
+
+// commentDirectiveNames is the set of directives recognized in proto comments.
+// Lines starting with any other "@word" are kept as documentation text.
+var commentDirectiveNames = map[string]bool{
+	"example":    true,
+	"deprecated": true,
+	"since":      true,
+	"security":   true,
+}
+
+// parseCommentDirectives splits a proto comment into its documentation text
+// and the directives it contains.
+func parseCommentDirectives(comment string) (string, []commentDirective) {
+	if !strings.Contains(comment, "@") {
+		return comment, nil
+	}
+
+	var (
+		lines      []string
+		directives []commentDirective
+		inValue    bool
+	)
+	for _, line := range strings.Split(comment, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "@") {
+			name, value := trimmed[1:], ""
+			if i := strings.IndexAny(name, " \t"); i != -1 {
+				name, value = name[:i], strings.TrimSpace(name[i:])
+			}
+			if commentDirectiveNames[name] {
+				directives = append(directives, commentDirective{name: name, value: value})
+				inValue = true
+				continue
+			}
+		}
+		if inValue {
+			if trimmed != "" {
+				d := &directives[len(directives)-1]
+				d.value = strings.TrimSpace(d.value + "\n" + trimmed)
+				continue
+			}
+			inValue = false
+			if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+				// don't leave two paragraph breaks where the directive was
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), directives
+}
+
+// applyCommentDirectives applies the directives parsed from a proto comment to
+// the OpenAPI object. Directives which the object can't represent are logged
+// and ignored.
+func applyCommentDirectives(swaggerObject interface{}, directives []commentDirective) {
+	if len(directives) == 0 {
+		return
+	}
+	objectValue := reflect.ValueOf(swaggerObject).Elem()
+	for _, d := range directives {
+		switch d.name {
+		case "example":
+			exampleValue := objectValue.FieldByName("Example")
+			if !exampleValue.CanSet() {
+				glog.Warningf("@example is not supported on %T", swaggerObject)
+				continue
+			}
+			example := json.RawMessage(d.value)
+			if !json.Valid(example) {
+				// not JSON, document it as a plain string
+				quoted, _ := json.Marshal(d.value)
+				example = json.RawMessage(quoted)
+			}
+			exampleValue.Set(reflect.ValueOf(example))
+		case "deprecated":
+			deprecatedValue := objectValue.FieldByName("Deprecated")
+			if deprecatedValue.Kind() == reflect.Bool && deprecatedValue.CanSet() {
+				deprecatedValue.SetBool(true)
+			} else if !addExtension(swaggerObject, "x-deprecated", true, extensionSourceComment) {
+				glog.Warningf("@deprecated is not supported on %T", swaggerObject)
+			}
+		case "since":
+			if !addExtension(swaggerObject, "x-since", d.value, extensionSourceComment) {
+				glog.Warningf("@since is not supported on %T", swaggerObject)
+			}
+		case "security":
+			operationObject, ok := swaggerObject.(*openapiOperationObject)
+			if !ok {
+				glog.Warningf("@security is not supported on %T", swaggerObject)
+				continue
+			}
+			addSecurityFromDirective(operationObject, d.value)
+		}
+	}
+}
+
+// addSecurityFromDirective adds the security requirement of a
+// "@security <name> [scope...]" directive to the operation. Every directive is
+// an alternative requirement. "@security none" documents that the operation
+// needs no authentication, overriding the top level security.
+func addSecurityFromDirective(operationObject *openapiOperationObject, value string) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		glog.Warningf("@security needs the name of a security definition")
+		return
+	}
+	security := []openapiSecurityRequirementObject{}
+	if operationObject.Security != nil {
+		security = *operationObject.Security
+	}
+	if fields[0] != "none" {
+		scopes := make([]string, 0, len(fields)-1)
+		scopes = append(scopes, fields[1:]...)
+		security = append(security, openapiSecurityRequirementObject{fields[0]: scopes})
+	}
+	operationObject.Security = &security
+}
+
+// addExtension appends a vendor extension to the OpenAPI objects which are able
+// to render them, and reports whether the object was one of those.
+func addExtension(swaggerObject interface{}, key string, value interface{}, source string) bool {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		glog.Errorf("failed to marshal extension %s: %v", key, err)
+		return false
+	}
+	ext := extension{key: key, value: json.RawMessage(raw), source: source}
+	switch o := swaggerObject.(type) {
+	case *openapiSwaggerObject:
+		o.extensions = append(o.extensions, ext)
+	case *openapiOperationObject:
+		o.extensions = append(o.extensions, ext)
+	case *openapiSchemaObject:
+		o.extensions = append(o.extensions, ext)
+	case *openapiResponseObject:
+		o.extensions = append(o.extensions, ext)
+	default:
+		return false
+	}
+	return true
+}
+
 func fieldProtoComments(reg *descriptor.Registry, msg *descriptor.Message, field *descriptor.Field) string {
 	protoPath := protoPathIndex(reflect.TypeOf((*descriptorpb.DescriptorProto)(nil)), "Field")
 	for i, f := range msg.Fields {
@@ -1771,6 +2107,32 @@ func fieldProtoComments(reg *descriptor.Registry, msg *descriptor.Message, field
 	return ""
 }
 
+// fieldPathDescription returns the description of a body or response_body which
+// points into a nested field. By default only the last field is documented,
+// using leafDescription if set, otherwise its comments. Depending on
+// the registry the comments of the outer fields are merged in as well, so that
+// the documentation chain stays intact when the body is flattened.
+func fieldPathDescription(reg *descriptor.Registry, fieldPath descriptor.FieldPath, leafDescription string) string {
+	var comments []string
+	for i, c := range fieldPath {
+		comment := ""
+		if i == len(fieldPath)-1 && leafDescription != "" {
+			comment = leafDescription
+		} else if i == len(fieldPath)-1 || reg.GetFieldPathCommentsOrder() != "" {
+			comment = fieldProtoComments(reg, c.Target.Message, c.Target)
+		}
+		if comment != "" {
+			comments = append(comments, comment)
+		}
+	}
+	if reg.GetFieldPathCommentsOrder() == "inner_first" {
+		for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+			comments[i], comments[j] = comments[j], comments[i]
+		}
+	}
+	return strings.Join(comments, "\n\n")
+}
+
 func enumValueProtoComments(reg *descriptor.Registry, enum *descriptor.Enum) string {
 	protoPath := protoPathIndex(reflect.TypeOf((*descriptorpb.EnumDescriptorProto)(nil)), "Value")
 	var comments []string
@@ -2379,3 +2741,38 @@ func find(a []string, x string) int {
 	}
 	return -1
 }
+
+// overrideResponseSchema returns the schema of a response override, in which
+// every {"$ref": "$response"} is replaced by the original response schema.
+func overrideResponseSchema(override descriptor.ResponseOverride, original openapiSchemaObject) (openapiSchemaObject, error) {
+	var schema openapiSchemaObject
+	if err := json.Unmarshal(override.Schema, &schema); err != nil {
+		return openapiSchemaObject{}, err
+	}
+	return replaceResponseRef(schema, original), nil
+}
+
+func replaceResponseRef(schema, original openapiSchemaObject) openapiSchemaObject {
+	if schema.Ref == descriptor.ResponseRef {
+		return original
+	}
+	if schema.Items != nil && schema.Items.Ref == descriptor.ResponseRef {
+		items := openapiItemsObject(original.schemaCore)
+		schema.Items = &items
+	}
+	if schema.AdditionalProperties != nil {
+		additional := replaceResponseRef(*schema.AdditionalProperties, original)
+		schema.AdditionalProperties = &additional
+	}
+	if schema.Properties != nil {
+		props := make(openapiSchemaObjectProperties, len(*schema.Properties))
+		for i, prop := range *schema.Properties {
+			if value, ok := prop.Value.(openapiSchemaObject); ok {
+				prop.Value = replaceResponseRef(value, original)
+			}
+			props[i] = prop
+		}
+		schema.Properties = &props
+	}
+	return schema
+}
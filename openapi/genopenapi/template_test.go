@@ -4185,6 +4185,118 @@ func TestUpdateOpenAPIDataFromComments(t *testing.T) {
 	}
 }
 
+func TestUpdateOpenAPIDataFromCommentDirectives(t *testing.T) {
+	tests := []struct {
+		descr                 string
+		openapiSwaggerObject  interface{}
+		comments              string
+		expectedOpenAPIObject interface{}
+	}{
+		{
+			descr:                "example on schema",
+			openapiSwaggerObject: &openapiSchemaObject{},
+			expectedOpenAPIObject: &openapiSchemaObject{
+				schemaCore: schemaCore{
+					Example: json.RawMessage(`{"id":1}`),
+				},
+				Title: "The user",
+			},
+			comments: "The user\n@example {\"id\":1}",
+		},
+		{
+			descr:                "multi line example",
+			openapiSwaggerObject: &openapiSchemaObject{},
+			expectedOpenAPIObject: &openapiSchemaObject{
+				schemaCore: schemaCore{
+					Example: json.RawMessage("{\n\"id\": 1\n}"),
+				},
+				Description: "The user.\n\nMore details.",
+			},
+			comments: "The user.\n\n@example {\n  \"id\": 1\n}\n\nMore details.",
+		},
+		{
+			descr:                "example which is not JSON",
+			openapiSwaggerObject: &openapiSchemaObject{},
+			expectedOpenAPIObject: &openapiSchemaObject{
+				schemaCore: schemaCore{
+					Example: json.RawMessage(`"users/1"`),
+				},
+			},
+			comments: "@example users/1",
+		},
+		{
+			descr:                "deprecated operation",
+			openapiSwaggerObject: &openapiOperationObject{},
+			expectedOpenAPIObject: &openapiOperationObject{
+				Summary:     "Get a user",
+				Description: "Deprecated: use GetUserV2",
+				Deprecated:  true,
+			},
+			comments: "Get a user\n@deprecated use GetUserV2",
+		},
+		{
+			descr:                "deprecated schema",
+			openapiSwaggerObject: &openapiSchemaObject{},
+			expectedOpenAPIObject: &openapiSchemaObject{
+				Description: "Old field.",
+				extensions:  []extension{{key: "x-deprecated", value: json.RawMessage("true")}},
+			},
+			comments: "Old field.\n@deprecated",
+		},
+		{
+			descr:                "since operation",
+			openapiSwaggerObject: &openapiOperationObject{},
+			expectedOpenAPIObject: &openapiOperationObject{
+				Summary:    "Get a user",
+				extensions: []extension{{key: "x-since", value: json.RawMessage(`"v1.4"`)}},
+			},
+			comments: "Get a user\n@since v1.4",
+		},
+		{
+			descr:                "security operation",
+			openapiSwaggerObject: &openapiOperationObject{},
+			expectedOpenAPIObject: &openapiOperationObject{
+				Summary: "Get a user",
+				Security: &[]openapiSecurityRequirementObject{
+					{"apiKey": []string{}},
+					{"oauth2": []string{"read:users", "write:users"}},
+				},
+			},
+			comments: "Get a user\n@security apiKey\n@security oauth2 read:users write:users",
+		},
+		{
+			descr:                "no security operation",
+			openapiSwaggerObject: &openapiOperationObject{},
+			expectedOpenAPIObject: &openapiOperationObject{
+				Summary:  "Health check",
+				Security: &[]openapiSecurityRequirementObject{},
+			},
+			comments: "Health check\n@security none",
+		},
+		{
+			descr:                "unknown directive is kept",
+			openapiSwaggerObject: &openapiSchemaObject{},
+			expectedOpenAPIObject: &openapiSchemaObject{
+				Description: "Contact @support for details.\n@owner team-a.",
+			},
+			comments: "Contact @support for details.\n@owner team-a.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.descr, func(t *testing.T) {
+			reg := descriptor.NewRegistry()
+			err := updateOpenAPIDataFromComments(reg, test.openapiSwaggerObject, nil, test.comments, false)
+			if err != nil {
+				t.Fatalf("unexpected error '%v'", err)
+			}
+			if !reflect.DeepEqual(test.openapiSwaggerObject, test.expectedOpenAPIObject) {
+				t.Errorf("openapiSwaggerObject was not updated correctly, expected '%+v', got '%+v'", test.expectedOpenAPIObject, test.openapiSwaggerObject)
+			}
+		})
+	}
+}
+
 func TestMessageOptionsWithGoTemplate(t *testing.T) {
 	tests := []struct {
 		descr          string
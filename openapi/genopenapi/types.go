@@ -55,8 +55,19 @@ type openapiExternalDocumentationObject struct {
 type extension struct {
 	key   string
 	value json.RawMessage
+	// source tells where the extension comes from, one of the
+	// extensionSource constants.
+	source string
 }
 
+// Sources of vendor extensions, reported by listExtensions.
+const (
+	extensionSourceOption    = "proto option"
+	extensionSourceComment   = "comment directive"
+	extensionSourceConfig    = "config file"
+	extensionSourceGenerated = "injected default"
+)
+
 // http://swagger.io/specification/#swaggerObject
 type openapiSwaggerObject struct {
 	Swagger             string                              `json:"swagger"`
@@ -121,6 +132,7 @@ type openapiOperationObject struct {
 	Parameters  openapiParametersObject `json:"parameters,omitempty"`
 	Tags        []string                `json:"tags,omitempty"`
 	Deprecated  bool                    `json:"deprecated,omitempty"`
+	Consumes    []string                `json:"consumes,omitempty"`
 	Produces    []string                `json:"produces,omitempty"`
 
 	Security     *[]openapiSecurityRequirementObject `json:"security,omitempty"`
@@ -144,7 +156,7 @@ type openapiParameterObject struct {
 	CollectionFormat string              `json:"collectionFormat,omitempty"`
 	Default          string              `json:"default,omitempty"`
 	MinItems         *int                `json:"minItems,omitempty"`
-	Ref             []map[string]string			 `json:"$ref,omitempty"`
+	Ref              string              `json:"$ref,omitempty"`
 	// Or you can explicitly refer to another type. If this is defined all
 	// other fields should be empty
 	Schema *openapiSchemaObject `json:"schema,omitempty"`
@@ -260,6 +272,8 @@ type openapiSchemaObject struct {
 	MaxProperties    uint64   `json:"maxProperties,omitempty"`
 	MinProperties    uint64   `json:"minProperties,omitempty"`
 	Required         []string `json:"required,omitempty"`
+
+	extensions []extension
 }
 
 // http://swagger.io/specification/#definitionsObject
@@ -0,0 +1,167 @@
+package genopenapi
+
+import "encoding/json"
+
+// The types below model the parts of OpenAPI 3.0 and 3.1 documents which a
+// swagger 2.0 document can be converted to. Objects which are the same in both
+// versions, like info, tags and external documentation, reuse the 2.0 types.
+
+// https://spec.openapis.org/oas/v3.0.3#openapi-object
+type openapiV3Document struct {
+	OpenAPI      string                              `json:"openapi"`
+	Info         openapiInfoObject                   `json:"info"`
+	Servers      []openapiV3ServerObject             `json:"servers,omitempty"`
+	Tags         []openapiTagObject                  `json:"tags,omitempty"`
+	Paths        map[string]openapiV3PathItemObject  `json:"paths"`
+	Components   *openapiV3ComponentsObject          `json:"components,omitempty"`
+	Security     []openapiSecurityRequirementObject  `json:"security,omitempty"`
+	ExternalDocs *openapiExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	extensions []extension
+}
+
+// https://spec.openapis.org/oas/v3.0.3#server-object
+type openapiV3ServerObject struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#components-object
+type openapiV3ComponentsObject struct {
+	Schemas         map[string]openapiV3SchemaObject         `json:"schemas,omitempty"`
+	Parameters      map[string]openapiV3ParameterObject      `json:"parameters,omitempty"`
+	SecuritySchemes map[string]openapiV3SecuritySchemeObject `json:"securitySchemes,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#security-scheme-object
+type openapiV3SecuritySchemeObject struct {
+	Type        string                     `json:"type"`
+	Description string                     `json:"description,omitempty"`
+	Name        string                     `json:"name,omitempty"`
+	In          string                     `json:"in,omitempty"`
+	Scheme      string                     `json:"scheme,omitempty"`
+	Flows       *openapiV3OAuthFlowsObject `json:"flows,omitempty"`
+
+	extensions []extension
+}
+
+// https://spec.openapis.org/oas/v3.0.3#oauth-flows-object
+type openapiV3OAuthFlowsObject struct {
+	Implicit          *openapiV3OAuthFlowObject `json:"implicit,omitempty"`
+	Password          *openapiV3OAuthFlowObject `json:"password,omitempty"`
+	ClientCredentials *openapiV3OAuthFlowObject `json:"clientCredentials,omitempty"`
+	AuthorizationCode *openapiV3OAuthFlowObject `json:"authorizationCode,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#oauth-flow-object
+type openapiV3OAuthFlowObject struct {
+	AuthorizationURL string              `json:"authorizationUrl,omitempty"`
+	TokenURL         string              `json:"tokenUrl,omitempty"`
+	Scopes           openapiScopesObject `json:"scopes"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#path-item-object
+type openapiV3PathItemObject struct {
+	Get    *openapiV3OperationObject `json:"get,omitempty"`
+	Delete *openapiV3OperationObject `json:"delete,omitempty"`
+	Post   *openapiV3OperationObject `json:"post,omitempty"`
+	Put    *openapiV3OperationObject `json:"put,omitempty"`
+	Patch  *openapiV3OperationObject `json:"patch,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#operation-object
+type openapiV3OperationObject struct {
+	Summary     string                             `json:"summary,omitempty"`
+	Description string                             `json:"description,omitempty"`
+	OperationID string                             `json:"operationId"`
+	Parameters  []openapiV3ParameterObject         `json:"parameters,omitempty"`
+	RequestBody *openapiV3RequestBodyObject        `json:"requestBody,omitempty"`
+	Responses   map[string]openapiV3ResponseObject `json:"responses"`
+	Tags        []string                           `json:"tags,omitempty"`
+	Deprecated  bool                               `json:"deprecated,omitempty"`
+
+	Security     *[]openapiSecurityRequirementObject `json:"security,omitempty"`
+	ExternalDocs *openapiExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	extensions []extension
+}
+
+// https://spec.openapis.org/oas/v3.0.3#parameter-object
+type openapiV3ParameterObject struct {
+	Ref         string                 `json:"$ref,omitempty"`
+	Name        string                 `json:"name,omitempty"`
+	In          string                 `json:"in,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Required    bool                   `json:"required,omitempty"`
+	Style       string                 `json:"style,omitempty"`
+	Explode     *bool                  `json:"explode,omitempty"`
+	Schema      *openapiV3SchemaObject `json:"schema,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#request-body-object
+type openapiV3RequestBodyObject struct {
+	Description string                              `json:"description,omitempty"`
+	Required    bool                                `json:"required,omitempty"`
+	Content     map[string]openapiV3MediaTypeObject `json:"content"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#media-type-object
+type openapiV3MediaTypeObject struct {
+	Schema  *openapiV3SchemaObject `json:"schema,omitempty"`
+	Example json.RawMessage        `json:"example,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#response-object
+type openapiV3ResponseObject struct {
+	Description string                              `json:"description"`
+	Headers     map[string]openapiV3HeaderObject    `json:"headers,omitempty"`
+	Content     map[string]openapiV3MediaTypeObject `json:"content,omitempty"`
+
+	extensions []extension
+}
+
+// https://spec.openapis.org/oas/v3.0.3#header-object
+type openapiV3HeaderObject struct {
+	Description string                 `json:"description,omitempty"`
+	Schema      *openapiV3SchemaObject `json:"schema,omitempty"`
+}
+
+// https://spec.openapis.org/oas/v3.0.3#schema-object
+type openapiV3SchemaObject struct {
+	Ref     string          `json:"$ref,omitempty"`
+	Type    string          `json:"type,omitempty"`
+	Format  string          `json:"format,omitempty"`
+	Example json.RawMessage `json:"example,omitempty"`
+	Enum    []string        `json:"enum,omitempty"`
+	Default json.RawMessage `json:"default,omitempty"`
+
+	Items *openapiV3SchemaObject `json:"items,omitempty"`
+	// Properties holds openapiV3SchemaObject values.
+	Properties           *openapiSchemaObjectProperties `json:"properties,omitempty"`
+	AdditionalProperties *openapiV3SchemaObject         `json:"additionalProperties,omitempty"`
+
+	Description string `json:"description,omitempty"`
+	Title       string `json:"title,omitempty"`
+
+	ExternalDocs *openapiExternalDocumentationObject `json:"externalDocs,omitempty"`
+
+	ReadOnly   bool    `json:"readOnly,omitempty"`
+	MultipleOf float64 `json:"multipleOf,omitempty"`
+	Maximum    float64 `json:"maximum,omitempty"`
+	// ExclusiveMaximum and ExclusiveMinimum are booleans in 3.0 and numbers
+	// in 3.1.
+	ExclusiveMaximum interface{} `json:"exclusiveMaximum,omitempty"`
+	Minimum          float64     `json:"minimum,omitempty"`
+	ExclusiveMinimum interface{} `json:"exclusiveMinimum,omitempty"`
+	MaxLength        uint64      `json:"maxLength,omitempty"`
+	MinLength        uint64      `json:"minLength,omitempty"`
+	Pattern          string      `json:"pattern,omitempty"`
+	MaxItems         uint64      `json:"maxItems,omitempty"`
+	MinItems         uint64      `json:"minItems,omitempty"`
+	UniqueItems      bool        `json:"uniqueItems,omitempty"`
+	MaxProperties    uint64      `json:"maxProperties,omitempty"`
+	MinProperties    uint64      `json:"minProperties,omitempty"`
+	Required         []string    `json:"required,omitempty"`
+
+	extensions []extension
+}
@@ -0,0 +1,224 @@
+package genopenapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// The UnmarshalJSON methods below are the counterparts of the MarshalJSON
+// methods in generator.go. They allow existing OpenAPI documents to be read
+// back into the same model the generator writes, keeping the "x-" extensions.
+
+// decodeOpenAPI reads an OpenAPI v2 document into the generator's model.
+// Fields which the model can't represent are dropped.
+func decodeOpenAPI(data []byte) (*openapiSwaggerObject, error) {
+	var swagger openapiSwaggerObject
+	if err := json.Unmarshal(data, &swagger); err != nil {
+		return nil, err
+	}
+	if swagger.Swagger != "2.0" {
+		return nil, fmt.Errorf("unsupported document version %q, only swagger 2.0 is supported", swagger.Swagger)
+	}
+	return &swagger, nil
+}
+
+func (so *openapiSwaggerObject) UnmarshalJSON(data []byte) error {
+	type alias openapiSwaggerObject
+	var a alias
+	exts, err := extensionUnmarshalJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	*so = openapiSwaggerObject(a)
+	so.extensions = exts
+	return nil
+}
+
+func (so *openapiInfoObject) UnmarshalJSON(data []byte) error {
+	type alias openapiInfoObject
+	var a alias
+	exts, err := extensionUnmarshalJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	*so = openapiInfoObject(a)
+	so.extensions = exts
+	return nil
+}
+
+func (so *openapiSecuritySchemeObject) UnmarshalJSON(data []byte) error {
+	type alias openapiSecuritySchemeObject
+	var a alias
+	exts, err := extensionUnmarshalJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	*so = openapiSecuritySchemeObject(a)
+	so.extensions = exts
+	return nil
+}
+
+func (so *openapiOperationObject) UnmarshalJSON(data []byte) error {
+	type alias openapiOperationObject
+	var a alias
+	exts, err := extensionUnmarshalJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	*so = openapiOperationObject(a)
+	so.extensions = exts
+	return nil
+}
+
+func (so *openapiResponseObject) UnmarshalJSON(data []byte) error {
+	type alias openapiResponseObject
+	var a alias
+	exts, err := extensionUnmarshalJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	*so = openapiResponseObject(a)
+	so.extensions = exts
+	return nil
+}
+
+func (so *openapiSchemaObject) UnmarshalJSON(data []byte) error {
+	data, err := stringifyScalars(data)
+	if err != nil {
+		return err
+	}
+	type alias openapiSchemaObject
+	var a alias
+	exts, err := extensionUnmarshalJSON(data, &a)
+	if err != nil {
+		return err
+	}
+	*so = openapiSchemaObject(a)
+	so.extensions = exts
+	return nil
+}
+
+func (so *openapiItemsObject) UnmarshalJSON(data []byte) error {
+	data, err := stringifyScalars(data)
+	if err != nil {
+		return err
+	}
+	type alias openapiItemsObject
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*so = openapiItemsObject(a)
+	return nil
+}
+
+func (so *openapiParameterObject) UnmarshalJSON(data []byte) error {
+	data, err := stringifyScalars(data)
+	if err != nil {
+		return err
+	}
+	type alias openapiParameterObject
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	*so = openapiParameterObject(a)
+	return nil
+}
+
+// UnmarshalJSON keeps the properties in the order of the document.
+func (op *openapiSchemaObjectProperties) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object, got %v", tok)
+	}
+	var props openapiSchemaObjectProperties
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("unexpected property key %v", tok)
+		}
+		var value openapiSchemaObject
+		if err := dec.Decode(&value); err != nil {
+			return fmt.Errorf("property %q: %v", key, err)
+		}
+		props = append(props, keyVal{Key: key, Value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	*op = props
+	return nil
+}
+
+// extensionUnmarshalJSON decodes data into so, which must be a pointer to an
+// alias type without UnmarshalJSON method, and returns the "x-" keys of the
+// object as extensions sorted by key.
+func extensionUnmarshalJSON(data []byte, so interface{}) ([]extension, error) {
+	if err := json.Unmarshal(data, so); err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var exts []extension
+	for k, v := range raw {
+		if strings.HasPrefix(k, "x-") {
+			exts = append(exts, extension{key: k, value: v})
+		}
+	}
+	sort.Slice(exts, func(i, j int) bool { return exts[i].key < exts[j].key })
+	return exts, nil
+}
+
+// stringifyScalars rewrites non-string "default" and "enum" values of a JSON
+// object to strings, since the model stores them the way the generator
+// renders them, as strings.
+func stringifyScalars(data []byte) ([]byte, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	_, hasDefault := raw["default"]
+	_, hasEnum := raw["enum"]
+	if !hasDefault && !hasEnum {
+		return data, nil
+	}
+	if hasDefault {
+		raw["default"] = stringifyScalar(raw["default"])
+	}
+	if hasEnum {
+		var values []json.RawMessage
+		if err := json.Unmarshal(raw["enum"], &values); err != nil {
+			return nil, fmt.Errorf("enum must be an array: %v", err)
+		}
+		for i, v := range values {
+			values[i] = stringifyScalar(v)
+		}
+		enum, err := json.Marshal(values)
+		if err != nil {
+			return nil, err
+		}
+		raw["enum"] = enum
+	}
+	return json.Marshal(raw)
+}
+
+func stringifyScalar(v json.RawMessage) json.RawMessage {
+	var s string
+	if err := json.Unmarshal(v, &s); err == nil {
+		return v
+	}
+	quoted, _ := json.Marshal(string(bytes.TrimSpace(v)))
+	return quoted
+}
@@ -0,0 +1,135 @@
+// Package openapitest provides helpers to write golden file tests for the
+// OpenAPI documents generated from a protoset, so that repositories embedding
+// the generator can guard the output of their own protos against regressions.
+//
+// A typical test looks like
+//
+//	func TestOpenAPI(t *testing.T) {
+//		openapitest.AssertGolden(t, "testdata/api.protoset", "testdata/golden",
+//			func(reg *descriptor.Registry) { reg.SetNamespace("/api") })
+//	}
+//
+// Running the tests with OPENAPITEST_UPDATE=1 rewrites the golden files with
+// the current output instead of comparing against them.
+package openapitest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/roverliang/grpc2openapi/openapi"
+	"github.com/roverliang/grpc2openapi/openapi/descriptor"
+	"github.com/roverliang/grpc2openapi/openapi/genopenapi"
+)
+
+// UpdateEnv is the environment variable which, when set to a non-empty value,
+// makes AssertGolden write the golden files instead of comparing them.
+const UpdateEnv = "OPENAPITEST_UPDATE"
+
+// Option configures the registry before the protoset is loaded into it.
+type Option func(reg *descriptor.Registry)
+
+// Generate loads the protoset at protosetPath and generates the OpenAPI files
+// of every proto file declaring a service, the same way the gen command does.
+func Generate(protosetPath string, opts ...Option) ([]*descriptor.ResponseFile, error) {
+	fds, err := openapi.LoadProtosetFile(protosetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := descriptor.NewRegistry()
+	for _, opt := range opts {
+		opt(reg)
+	}
+	if err := reg.Load(fds); err != nil {
+		return nil, fmt.Errorf("failed to load protoset: %v", err)
+	}
+
+	var targets []*descriptor.File
+	for _, fd := range fds {
+		name := fd.GetName()
+		if strings.Contains(name, descriptor.ReflectionProto) || !openapi.HasServices(fd) {
+			continue
+		}
+		f, err := reg.LookupFile(name)
+		if err != nil {
+			if reg.IsLenientLoad() {
+				continue
+			}
+			return nil, err
+		}
+		targets = append(targets, f)
+	}
+	return genopenapi.New(reg).Generate(targets)
+}
+
+// AssertGolden generates the OpenAPI files of the protoset and compares each
+// of them with the file of the same name in goldenDir, reporting a line diff
+// for every mismatch. Golden files which are not generated anymore are
+// reported as well.
+func AssertGolden(t testing.TB, protosetPath, goldenDir string, opts ...Option) {
+	t.Helper()
+
+	files, err := Generate(protosetPath, opts...)
+	if err != nil {
+		t.Fatalf("failed to generate OpenAPI files from %s: %v", protosetPath, err)
+	}
+
+	if os.Getenv(UpdateEnv) != "" {
+		for _, f := range files {
+			goldenPath := filepath.Join(goldenDir, f.GetName())
+			if err := os.MkdirAll(filepath.Dir(goldenPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(goldenPath, []byte(f.GetContent()), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	generated := make(map[string]bool, len(files))
+	for _, f := range files {
+		goldenPath := filepath.Join(goldenDir, f.GetName())
+		want, err := ioutil.ReadFile(goldenPath)
+		if err != nil {
+			t.Errorf("failed to read golden file, run with %s=1 to create it: %v", UpdateEnv, err)
+			continue
+		}
+		generated[goldenPath] = true
+		if diff := Diff(string(want), f.GetContent()); diff != "" {
+			t.Errorf("%s mismatch (-want +got), run with %s=1 to update:\n%s", goldenPath, UpdateEnv, diff)
+		}
+	}
+
+	err = filepath.Walk(goldenDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == goldenDir {
+				// already reported as missing golden files
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() && strings.HasSuffix(path, ".json") && !generated[path] {
+			t.Errorf("golden file %s is not generated anymore", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Diff returns a line oriented diff of two documents, or an empty string if
+// they are equal.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	return cmp.Diff(strings.Split(want, "\n"), strings.Split(got, "\n"))
+}
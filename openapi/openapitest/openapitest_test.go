@@ -0,0 +1,17 @@
+package openapitest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	if diff := Diff("{\n  \"a\": 1\n}\n", "{\n  \"a\": 1\n}\n"); diff != "" {
+		t.Errorf("Diff of equal documents = %q, want empty", diff)
+	}
+
+	diff := Diff("{\n  \"a\": 1\n}\n", "{\n  \"a\": 2\n}\n")
+	if !strings.Contains(diff, `\"a\": 1`) || !strings.Contains(diff, `\"a\": 2`) {
+		t.Errorf("Diff does not show the changed line:\n%s", diff)
+	}
+}